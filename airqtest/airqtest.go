@@ -0,0 +1,142 @@
+// Package airqtest provides in-memory queues for unit tests, running without
+// Redis, with a fake clock to fast-forward scheduled jobs and assertions on
+// the pushed jobs:
+//
+//	q := airqtest.New(t, "emails")
+//	sendLater(q.Queue) // code under test, pushing to an *airq.Queue
+//	q.AssertPushed("welcome")
+//	q.Advance(time.Hour)
+//	job, _ := q.Pop()
+package airqtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jney/airq"
+)
+
+// Queue is an airq.Queue keeping jobs in memory, its clock only moving with
+// Advance and SetTime.
+type Queue struct {
+	*airq.Queue
+	t testing.TB
+
+	mu     sync.Mutex
+	now    time.Time
+	pushed []*airq.Job
+}
+
+// New returns an empty queue, its clock set to the current time. Options are
+// those of airq.New, the store and the clock being replaced.
+func New(t testing.TB, name string, opts ...airq.Option) *Queue {
+	q := &Queue{t: t, now: time.Now()}
+	q.Queue = airq.New(name, append(opts,
+		airq.WithStore(recorder{Store: airq.NewMemoryStore(), q: q}),
+		airq.WithClock(q.Now),
+	)...)
+	return q
+}
+
+// Now returns the time of the queue clock.
+func (q *Queue) Now() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.now
+}
+
+// Advance moves the queue clock forward by d, scheduled jobs becoming due.
+func (q *Queue) Advance(d time.Duration) {
+	q.mu.Lock()
+	q.now = q.now.Add(d)
+	q.mu.Unlock()
+}
+
+// SetTime sets the queue clock.
+func (q *Queue) SetTime(now time.Time) {
+	q.mu.Lock()
+	q.now = now
+	q.mu.Unlock()
+}
+
+// Pushed returns the jobs pushed with subject, in order, all of them if
+// subject is empty.
+func (q *Queue) Pushed(subject string) []*airq.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var jobs []*airq.Job
+	for _, j := range q.pushed {
+		if subject == "" || j.Subject == subject {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// Reset forgets the pushed jobs, keeping those in the queue.
+func (q *Queue) Reset() {
+	q.mu.Lock()
+	q.pushed = nil
+	q.mu.Unlock()
+}
+
+// AssertPushed fails the test if no job was pushed with subject, and returns
+// the last one.
+func (q *Queue) AssertPushed(subject string) *airq.Job {
+	q.t.Helper()
+	jobs := q.Pushed(subject)
+	if len(jobs) == 0 {
+		q.t.Errorf("airqtest: expected a job with subject %q pushed to %s", subject, q.Name)
+		return nil
+	}
+	return jobs[len(jobs)-1]
+}
+
+// AssertNotPushed fails the test if a job was pushed with subject.
+func (q *Queue) AssertNotPushed(subject string) {
+	q.t.Helper()
+	if jobs := q.Pushed(subject); len(jobs) > 0 {
+		q.t.Errorf("airqtest: expected no job with subject %q pushed to %s, got %d", subject, q.Name, len(jobs))
+	}
+}
+
+// AssertPending fails the test if the queue doesn't have n pending jobs,
+// including scheduled ones.
+func (q *Queue) AssertPending(n int64) {
+	q.t.Helper()
+	if pending, err := q.Pending(); err != nil || pending != n {
+		q.t.Errorf("airqtest: expected %d jobs pending in %s, got %d (%v)", n, q.Name, pending, err)
+	}
+}
+
+// AssertDue fails the test if the queue doesn't have n jobs due now.
+func (q *Queue) AssertDue(n int64) {
+	q.t.Helper()
+	if stats, err := q.Stats(); err != nil || stats.Due != n {
+		q.t.Errorf("airqtest: expected %d jobs due in %s, got %d (%v)", n, q.Name, stats.Due, err)
+	}
+}
+
+// recorder is a Store recording the pushed jobs.
+type recorder struct {
+	airq.Store
+	q *Queue
+}
+
+func (r recorder) Push(jobs ...airq.StoredJob) ([][]byte, error) {
+	replaced, err := r.Store.Push(jobs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, stored := range jobs {
+		j, err := r.q.Decode(stored.Value)
+		if err != nil {
+			return replaced, err
+		}
+		r.q.mu.Lock()
+		r.q.pushed = append(r.q.pushed, j)
+		r.q.mu.Unlock()
+	}
+	return replaced, nil
+}
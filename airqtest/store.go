@@ -0,0 +1,103 @@
+package airqtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jney/airq"
+)
+
+// TestStore runs the operations of q on its store, q being empty, e.g. to test
+// an airq.Store implementation:
+//
+//	airqtest.TestStore(t, airq.New("test", airq.WithStore(store)))
+func TestStore(t testing.TB, q *airq.Queue) {
+	t.Helper()
+	now := time.Now()
+	push(t, q,
+		&airq.Job{ID: "a", Content: "a", When: now.Add(-3 * time.Second)},
+		&airq.Job{ID: "b", Content: "b", When: now.Add(-2 * time.Second)},
+		&airq.Job{ID: "c", Content: "c", When: now.Add(-time.Second)},
+		&airq.Job{ID: "later", Content: "later", When: now.Add(time.Hour)},
+	)
+	if stats, err := q.Stats(); err != nil || stats != (airq.Stats{Pending: 4, Due: 3}) {
+		t.Error("Expected 4 jobs pending, 3 due, got", stats, err)
+	}
+	if jobs, err := q.List(1, 2); err != nil || len(jobs) != 2 || jobs[0].ID != "b" || jobs[1].ID != "c" {
+		t.Error("Expected jobs b and c, got", jobs, err)
+	}
+	if jobs, err := q.List(3, 2); err != nil || len(jobs) != 1 || jobs[0].ID != "later" {
+		t.Error("Expected the later job, got", jobs, err)
+	}
+	// pushing an id again replaces the job
+	push(t, q, &airq.Job{ID: "a", Content: "a again", When: now.Add(-3 * time.Second)})
+	if j, err := q.Get("a"); err != nil || j == nil || j.Content != "a again" {
+		t.Error("Expected job a replaced, got", j, err)
+	}
+
+	job, err := q.Pop()
+	if err != nil || job == nil || job.ID != "a" || job.Content != "a again" {
+		t.Error("Expected job a popped, got", job, err)
+	}
+	jobs, err := q.Reserve(1, time.Minute)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "b" {
+		t.Error("Expected job b reserved, got", jobs, err)
+	}
+	if j, err := q.Get("b"); err != nil || j == nil || j.Content != "b" {
+		t.Error("Expected reserved job b, got", j, err)
+	}
+	if err := q.Nack(0, "b"); err != nil {
+		t.Error(err)
+	}
+	if err := q.Nack(0, "b"); !errors.Is(err, airq.ErrNotReserved) {
+		t.Error("Expected airq.ErrNotReserved, got", err)
+	}
+	if jobs, err = q.Reserve(5, -time.Second); err != nil || len(jobs) != 2 {
+		t.Error("Expected jobs c and b reserved, got", jobs, err)
+	}
+	// expired reservations are pending again
+	if jobs, err = q.Reserve(5, time.Minute); err != nil || len(jobs) != 2 {
+		t.Error("Expected 2 expired jobs reserved again, got", jobs, err)
+	}
+	if err := q.Ack("b", "c"); err != nil {
+		t.Error(err)
+	}
+	if j, err := q.Get("b"); err != nil || j != nil {
+		t.Error("Expected job b acknowledged, got", j, err)
+	}
+
+	if err := q.Reschedule(now.Add(-time.Second), "later"); err != nil {
+		t.Error(err)
+	}
+	if err := q.Reschedule(now, "missing"); !errors.Is(err, airq.ErrNotFound) {
+		t.Error("Expected airq.ErrNotFound, got", err)
+	}
+	if pending, err := q.Pending(); err != nil || pending != 1 {
+		t.Error("Expected 1 job pending, got", pending, err)
+	}
+	if err := q.Remove("later", "missing"); !errors.Is(err, airq.ErrNotFound) {
+		t.Error("Expected airq.ErrNotFound, got", err)
+	}
+	if pending, _ := q.Pending(); pending != 0 {
+		t.Error("Expected no job pending, got", pending)
+	}
+
+	push(t, q, &airq.Job{Content: "x"}, &airq.Job{Content: "y"})
+	if _, err := q.Reserve(1, time.Minute); err != nil {
+		t.Error(err)
+	}
+	if n, err := q.Purge(); err != nil || n != 2 {
+		t.Error("Expected 2 jobs purged, got", n, err)
+	}
+	if stats, _ := q.Stats(); stats != (airq.Stats{}) {
+		t.Error("Expected an empty queue, got", stats)
+	}
+}
+
+func push(t testing.TB, q *airq.Queue, jobs ...*airq.Job) {
+	t.Helper()
+	if _, err := q.Push(jobs...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package airq_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/airqtest"
+)
+
+// fakeT records the failures of assertions expected to fail.
+type fakeT struct {
+	*testing.T
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAirqtest(t *testing.T) {
+	q := airqtest.New(t, "emails")
+	type welcome struct{ Name string }
+	tq := airq.NewTypedQueue[welcome](q.Queue, "welcome", airq.JSONSerializer{})
+	if _, err := tq.Push(welcome{Name: "joe"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := q.Push(&airq.Job{Content: "reminder", Subject: "reminder", When: q.Now().Add(time.Hour)}); err != nil {
+		t.Error(err)
+	}
+
+	j := q.AssertPushed("welcome")
+	if v, err := tq.Decode(j); err != nil || v.Name != "joe" {
+		t.Error("Expected the welcome job of joe, got", v, err)
+	}
+	q.AssertPending(2)
+	q.AssertDue(1)
+	if j, _ := q.Pop(); j == nil || j.Subject != "welcome" {
+		t.Error("Expected the welcome job popped, got", j)
+	}
+	if j, _ := q.Pop(); j != nil {
+		t.Error("Expected the reminder not due yet, got", j)
+	}
+	q.Advance(time.Hour)
+	q.AssertDue(1)
+	if jobs, _ := q.Reserve(1, time.Minute); len(jobs) != 1 || jobs[0].Subject != "reminder" {
+		t.Error("Expected the reminder reserved, got", jobs)
+	}
+	// the reservation expires with the clock
+	q.Advance(time.Minute)
+	if jobs, _ := q.Reserve(1, time.Minute); len(jobs) != 1 {
+		t.Error("Expected the reminder reserved again, got", jobs)
+	}
+
+	ft := &fakeT{T: t}
+	fq := airqtest.New(ft, "other")
+	fq.Push(&airq.Job{Content: "x", Subject: "x"})
+	fq.AssertPushed("y")
+	fq.AssertNotPushed("x")
+	fq.AssertPending(0)
+	if len(ft.errors) != 3 {
+		t.Error("Expected 3 failed assertions, got", ft.errors)
+	}
+	fq.Reset()
+	if jobs := fq.Pushed(""); len(jobs) != 0 {
+		t.Error("Expected no job recorded after Reset, got", jobs)
+	}
+}
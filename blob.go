@@ -0,0 +1,142 @@
+package airq
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rs/xid"
+	"github.com/shamaton/msgpackgen/msgpack"
+)
+
+// BlobStore stores large jobs outside of the queue.
+type BlobStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(keys ...string) error
+}
+
+// WithMaxPayloadSize rejects jobs whose Content and Payload exceed size bytes.
+func WithMaxPayloadSize(size int) Option { return func(q *Queue) { q.maxPayloadSize = size } }
+
+// WithOffload stores jobs whose Content and Payload exceed threshold bytes
+// under their own redis key instead of the queue values hash.
+func WithOffload(threshold int) Option {
+	return func(q *Queue) { q.blobs, q.offloadThreshold = redisBlobStore{q}, threshold }
+}
+
+// WithBlobStore stores jobs whose Content and Payload exceed threshold bytes in s.
+func WithBlobStore(s BlobStore, threshold int) Option {
+	return func(q *Queue) { q.blobs, q.offloadThreshold = s, threshold }
+}
+
+// blobKey returns a new key for the blob of a job, unique to each push so that
+// pushing a job again doesn't touch the blob of the stored one.
+func (q *Queue) blobKey(id string) string { return q.key() + ":blobs:" + id + ":" + xid.New().String() }
+
+// offload puts the encoded job in the blob store and returns the encoded stub
+// to push in its place.
+func (q *Queue) offload(j *Job, b []byte) ([]byte, string, error) {
+	key := q.blobKey(j.ID)
+	if err := q.blobs.Put(key, b); err != nil {
+		return nil, "", err
+	}
+	stub := &Job{ID: j.ID, Headers: j.Headers, Subject: j.Subject, When: j.When, Blob: key}
+	return stub.Bytes(), key, nil
+}
+
+// restore fetches the job offloaded by a stub.
+func (q *Queue) restore(stub *Job) (*Job, error) {
+	b, err := q.blobs.Get(stub.Blob)
+	if err != nil {
+		return nil, err
+	}
+	return newJobFromBytes(b, q.keys)
+}
+
+// dropBlobs deletes the blobs offloaded by the stubs of removed or replaced
+// jobs, only their values telling which blobs they point to.
+func (q *Queue) dropBlobs(values [][]byte) error {
+	if q.blobs == nil {
+		return nil
+	}
+	var keys []string
+	for _, v := range values {
+		var stub Job
+		if err := msgpack.Unmarshal(v, &stub); err == nil && stub.Blob != "" {
+			keys = append(keys, stub.Blob)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return q.blobs.Delete(keys...)
+}
+
+// deleteBlobs deletes blobs offloaded by a failed push.
+func (q *Queue) deleteBlobs(keys []string) {
+	if len(keys) > 0 {
+		q.blobs.Delete(keys...)
+	}
+}
+
+type redisBlobStore struct{ q *Queue }
+
+func (s redisBlobStore) Put(key string, data []byte) error {
+	c, release := s.q.exec()
+	defer release()
+	_, err := c.Do("SET", key, data)
+	return err
+}
+
+func (s redisBlobStore) Get(key string) ([]byte, error) {
+	c, release := s.q.exec()
+	defer release()
+	return redis.Bytes(c.Do("GET", key))
+}
+
+func (s redisBlobStore) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c, release := s.q.exec()
+	defer release()
+	_, err := c.Do("DEL", redis.Args{}.AddFlat(keys)...)
+	return err
+}
+
+// FileBlobStore is a BlobStore keeping each blob in a file of Dir.
+type FileBlobStore struct {
+	Dir string
+}
+
+// NewFileBlobStore defines a FileBlobStore, creating dir if needed.
+func NewFileBlobStore(dir string) (*FileBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileBlobStore{Dir: dir}, nil
+}
+
+func (s *FileBlobStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key))
+}
+
+func (s *FileBlobStore) Put(key string, data []byte) error {
+	return ioutil.WriteFile(s.path(key), data, 0o600)
+}
+
+func (s *FileBlobStore) Get(key string) ([]byte, error) {
+	return ioutil.ReadFile(s.path(key))
+}
+
+func (s *FileBlobStore) Delete(keys ...string) error {
+	for _, key := range keys {
+		if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
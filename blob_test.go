@@ -0,0 +1,203 @@
+package airq
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/shamaton/msgpackgen/msgpack"
+)
+
+func TestMaxPayloadSize(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	WithMaxPayloadSize(10)(q)
+
+	if _, err := q.Push(&Job{Content: "small"}); err != nil {
+		t.Error(err)
+	}
+	_, err := q.Push(&Job{Content: "small"}, &Job{Content: strings.Repeat("x", 11)})
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Error("Expected ErrPayloadTooLarge, got", err)
+	}
+	pending, _ := q.Pending()
+	if pending != 1 {
+		t.Error("Expected 1 job pending in queue, was", pending)
+	}
+}
+
+func TestOffload(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	WithOffload(10)(q)
+
+	large := strings.Repeat("large item ", 10)
+	ids, err := q.Push(&Job{Content: "small"}, &Job{Content: large, Subject: "big"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	c, _ := q.Conn()
+	key := blobOf(t, q, ids[1])
+	if exists, _ := redis.Bool(c.Do("EXISTS", key)); !exists {
+		t.Error("Expected large job to be offloaded")
+	}
+	if blobOf(t, q, ids[0]) != "" {
+		t.Error("Didn't expect small job to be offloaded")
+	}
+
+	jobs, err := q.PopJobs(2)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[1].Content != large || jobs[1].Subject != "big" {
+		t.Error("Expected to get the offloaded job back, got", jobs)
+	}
+	if exists, _ := redis.Bool(c.Do("EXISTS", key)); exists {
+		t.Error("Expected offloaded job to be deleted once popped")
+	}
+}
+
+func TestFileBlobStore(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	store, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	WithBlobStore(store, 0)(q)
+
+	payload := []byte(strings.Repeat("payload", 10))
+	ids, err := q.Push(&Job{Payload: payload}, &Job{Content: "removed", ID: "01"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if _, err := store.Get(blobOf(t, q, ids[0])); err != nil {
+		t.Error("Expected job to be offloaded", err)
+	}
+
+	removed := blobOf(t, q, "01")
+	if err := q.Remove("01"); err != nil {
+		t.Error(err)
+	}
+	if _, err := store.Get(removed); err == nil {
+		t.Error("Expected removed job blob to be deleted")
+	}
+
+	job, err := q.Pop()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if string(job.Payload) != string(payload) {
+		t.Error("Expected to get the offloaded payload back, got", job.Payload)
+	}
+}
+
+func TestOffloadAck(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	WithOffload(0)(q)
+	c, _ := q.Conn()
+
+	addJobs(t, q, Job{ID: "01", Content: "reserved"}, Job{ID: "02", Content: "pending"})
+	if _, err := q.Reserve(1, time.Minute); err != nil {
+		t.Error(err)
+	}
+	// pushed again while reserved
+	addJobs(t, q, Job{ID: "01", Content: "pushed again"})
+
+	if err := q.Ack("01", "02"); !errors.Is(err, ErrNotReserved) {
+		t.Error("Expected ErrNotReserved, got", err)
+	}
+	for _, id := range []string{"01", "02"} {
+		if exists, _ := redis.Bool(c.Do("EXISTS", blobOf(t, q, id))); !exists {
+			t.Error("Expected the blob of pending job", id, "to be kept")
+		}
+	}
+	jobs, err := q.PopJobs(2)
+	if err != nil || len(jobs) != 2 || jobs[0].Content != "pending" || jobs[1].Content != "pushed again" {
+		t.Error("Expected the pending jobs, got", jobs, err)
+	}
+}
+
+func TestOffloadOverwrite(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	blobs, err := NewFileBlobStore(dir)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	store := &failingStore{Store: NewMemoryStore()}
+	q := New(randomName(), WithStore(store), WithBlobStore(blobs, 10))
+
+	large := strings.Repeat("large item ", 10)
+	addJobs(t, q, Job{ID: "01", Content: large})
+	first := blobOf(t, q, "01")
+	addJobs(t, q, Job{ID: "01", Content: large + "again"})
+	second := blobOf(t, q, "01")
+	if _, err := blobs.Get(first); err == nil || first == second {
+		t.Error("Expected the blob of the replaced job to be deleted")
+	}
+
+	// a failed push keeps the blob of the stored job
+	store.fail = true
+	if _, err := q.Push(&Job{ID: "01", Content: large}); err == nil {
+		t.Error("Expected the push to fail")
+	}
+	store.fail = false
+	if j, err := q.Get("01"); err != nil || j == nil || j.Content != large+"again" {
+		t.Error("Expected the stored job to be kept, got", j, err)
+	}
+
+	// jobs that can't be restored are pushed back
+	os.Remove(blobs.path(second))
+	if jobs, err := q.PopJobs(1); err == nil || len(jobs) != 0 {
+		t.Error("Expected an error for a missing blob, got", jobs, err)
+	}
+	if pending, _ := q.Pending(); pending != 1 {
+		t.Error("Expected the job to be pushed back, got", pending)
+	}
+
+	// pushed again below the threshold
+	addJobs(t, q, Job{ID: "01", Content: "small"})
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Error("Expected no blob left, got", files)
+	}
+}
+
+// failingStore is a Store whose pushes fail if fail is true.
+type failingStore struct {
+	Store
+	fail bool
+}
+
+func (s *failingStore) Push(jobs ...StoredJob) ([][]byte, error) {
+	if s.fail {
+		return nil, errors.New("push failed")
+	}
+	return s.Store.Push(jobs...)
+}
+
+// blobOf returns the blob key of a stored job, empty if not offloaded.
+func blobOf(t *testing.T, q *Queue, id string) string {
+	t.Helper()
+	b, err := q.store.Get(id)
+	if err != nil || b == nil {
+		t.Error("Expected job", id, "to be stored", err)
+		return ""
+	}
+	var stub Job
+	if err := msgpack.Unmarshal(b, &stub); err != nil {
+		t.Error(err)
+	}
+	return stub.Blob
+}
@@ -2,46 +2,220 @@ package client
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/jney/airq"
+	"github.com/jney/airq/internal/convert"
+	"github.com/jney/airq/internal/rpcerr"
 	"github.com/jney/airq/job"
 	"google.golang.org/grpc"
 )
 
 type Client struct {
 	Conn *grpc.ClientConn
+	// Queue is the name of the queue requests are routed to, the server default queue if empty.
+	Queue string
+}
+
+// ConsumeOptions configures Consume.
+type ConsumeOptions struct {
+	// Size is the maximum count of jobs received at once, 100 by default.
+	Size int
+	// Sleep is the delay between two polls of an empty queue by the server, 3s by default.
+	Sleep time.Duration
+	// Visibility reserves jobs instead of removing them: jobs are acknowledged
+	// when the handler succeeds and released otherwise.
+	Visibility time.Duration
+	// RetryDelay is the delay before a released job is due again.
+	RetryDelay time.Duration
 }
 
 func New(conn *grpc.ClientConn) *Client {
 	return &Client{Conn: conn}
 }
 
+// Close closes the client connection.
+func (c *Client) Close() error {
+	return c.Conn.Close()
+}
+
+// WithQueue returns a copy of the client routing requests to the named queue.
+func (c *Client) WithQueue(name string) *Client {
+	cli := *c
+	cli.Queue = name
+	return &cli
+}
+
 func (c *Client) Push(ctx context.Context, jobs ...*airq.Job) (*job.IdList, error) {
 	if len(jobs) == 0 {
 		return new(job.IdList), nil
 	}
-	jobList := new(job.JobList)
-	for _, j := range jobs {
-		jobList.Jobs = append(jobList.Jobs, &job.Job{
-			Id:       j.ID,
-			Content:  j.Content,
-			Strategy: int32(j.Strategy),
-			When:     j.When.UnixNano(),
-		})
-	}
+	jobList := convert.ToList(jobs)
+	jobList.Queue = c.Queue
 	client := job.NewJobsClient(c.Conn)
-	return client.Push(ctx, jobList)
+	ids, err := client.Push(ctx, jobList)
+	return ids, rpcerr.FromStatus(err)
 }
 
 func (c *Client) Remove(ctx context.Context, ids ...string) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	idList := new(job.IdList)
-	for _, i := range ids {
-		idList.Ids = append(idList.Ids, &job.Id{Id: i})
+	client := job.NewJobsClient(c.Conn)
+	_, err := client.Remove(ctx, convert.IDList(c.Queue, ids))
+	return rpcerr.FromStatus(err)
+}
+
+// Pop removes and returns up to limit due jobs.
+func (c *Client) Pop(ctx context.Context, limit int) ([]*airq.Job, error) {
+	client := job.NewJobsClient(c.Conn)
+	l, err := client.Pop(ctx, &job.PopRequest{Limit: int32(limit), Queue: c.Queue})
+	return convert.FromList(l), rpcerr.FromStatus(err)
+}
+
+// Reserve returns up to limit due jobs, to be acknowledged with Ack before visibility.
+func (c *Client) Reserve(ctx context.Context, limit int, visibility time.Duration) ([]*airq.Job, error) {
+	client := job.NewJobsClient(c.Conn)
+	l, err := client.Pop(ctx, &job.PopRequest{
+		Limit:      int32(limit),
+		Queue:      c.Queue,
+		Visibility: int64(visibility),
+	})
+	return convert.FromList(l), rpcerr.FromStatus(err)
+}
+
+// Pending returns the count of jobs pending, including scheduled jobs that are not due yet.
+func (c *Client) Pending(ctx context.Context) (int64, error) {
+	client := job.NewJobsClient(c.Conn)
+	n, err := client.Pending(ctx, &job.Queue{Name: c.Queue})
+	return n.GetCount(), rpcerr.FromStatus(err)
+}
+
+// Stats returns the job counters of the queue.
+func (c *Client) Stats(ctx context.Context) (airq.Stats, error) {
+	client := job.NewJobsClient(c.Conn)
+	s, err := client.Stats(ctx, &job.Queue{Name: c.Queue})
+	if err != nil {
+		return airq.Stats{}, rpcerr.FromStatus(err)
 	}
+	return airq.Stats{Pending: s.GetPending(), Due: s.GetDue(), Reserved: s.GetReserved()}, nil
+}
+
+// Purge removes all the jobs of the queue, pending or reserved, and returns their count.
+func (c *Client) Purge(ctx context.Context) (int64, error) {
+	client := job.NewJobsClient(c.Conn)
+	n, err := client.Purge(ctx, &job.Queue{Name: c.Queue})
+	return n.GetCount(), rpcerr.FromStatus(err)
+}
+
+// Peek returns the next jobs of the queue without removing them.
+func (c *Client) Peek(ctx context.Context, limit int) ([]*airq.Job, error) {
+	client := job.NewJobsClient(c.Conn)
+	l, err := client.Peek(ctx, &job.PeekRequest{Limit: int32(limit), Queue: c.Queue})
+	return convert.FromList(l), rpcerr.FromStatus(err)
+}
+
+// Get returns a pending or reserved job by id.
+func (c *Client) Get(ctx context.Context, id string) (*airq.Job, error) {
 	client := job.NewJobsClient(c.Conn)
-	_, err := client.Remove(ctx, idList)
-	return err
+	j, err := client.Get(ctx, &job.GetRequest{Id: id, Queue: c.Queue})
+	if err != nil {
+		return nil, rpcerr.FromStatus(err)
+	}
+	return convert.FromProto(j), nil
+}
+
+// Ack acknowledges reserved jobs.
+func (c *Client) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	client := job.NewJobsClient(c.Conn)
+	_, err := client.Ack(ctx, convert.IDList(c.Queue, ids))
+	return rpcerr.FromStatus(err)
+}
+
+// Nack releases reserved jobs, to be processed again after delay.
+func (c *Client) Nack(ctx context.Context, delay time.Duration, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	client := job.NewJobsClient(c.Conn)
+	_, err := client.Nack(ctx, &job.NackRequest{
+		Ids:   convert.IDList(c.Queue, ids).Ids,
+		Delay: int64(delay),
+		Queue: c.Queue,
+	})
+	return rpcerr.FromStatus(err)
+}
+
+// Reschedule changes the execution time of pending jobs.
+func (c *Client) Reschedule(ctx context.Context, when time.Time, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	client := job.NewJobsClient(c.Conn)
+	_, err := client.Reschedule(ctx, &job.RescheduleRequest{
+		Ids:   convert.IDList(c.Queue, ids).Ids,
+		When:  when.UnixNano(),
+		Queue: c.Queue,
+	})
+	return rpcerr.FromStatus(err)
+}
+
+// Consume processes jobs streamed by the server with handler, until ctx is
+// done or the stream is broken. The server sends the next jobs once the
+// previous ones are processed.
+func (c *Client) Consume(ctx context.Context, opts *ConsumeOptions, handler func(*airq.Job) error) error {
+	if opts == nil {
+		opts = new(ConsumeOptions)
+	}
+	client := job.NewJobsClient(c.Conn)
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		return rpcerr.FromStatus(err)
+	}
+	req := &job.SubscribeRequest{
+		Queue:      c.Queue,
+		Size:       int32(opts.Size),
+		Sleep:      int64(opts.Sleep),
+		Visibility: int64(opts.Visibility),
+	}
+	// a broken stream fails Send with io.EOF, its error being returned by Recv
+	if err := stream.Send(req); err != nil && err != io.EOF {
+		return rpcerr.FromStatus(err)
+	}
+	for {
+		l, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return rpcerr.FromStatus(err)
+		}
+		for _, j := range convert.FromList(l) {
+			err := handler(j)
+			if opts.Visibility == 0 {
+				continue
+			}
+			// the job has been processed, acknowledge it even if ctx is done
+			if err != nil {
+				err = c.Nack(context.Background(), opts.RetryDelay, j.ID)
+			} else {
+				err = c.Ack(context.Background(), j.ID)
+			}
+			// processed after the visibility timeout, the job has been queued again
+			if err != nil && !errors.Is(err, airq.ErrNotReserved) {
+				return err
+			}
+		}
+		if err := stream.Send(req); err != nil && err != io.EOF {
+			return rpcerr.FromStatus(err)
+		}
+	}
 }
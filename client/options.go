@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Option configures the connection opened by Dial.
+type Option func(*config)
+
+type config struct {
+	certs              []tls.Certificate
+	dialOptions        []grpc.DialOption
+	rootCAs            *x509.CertPool
+	streamInterceptors []grpc.StreamClientInterceptor
+	tls                bool
+	token              string
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+}
+
+// WithTLS connects over TLS, verifying the server certificate with rootCAs
+// (the system pool if nil).
+func WithTLS(rootCAs *x509.CertPool) Option {
+	return func(c *config) { c.tls, c.rootCAs = true, rootCAs }
+}
+
+// WithCertificate presents cert to the server (mutual TLS), it implies WithTLS.
+func WithCertificate(cert tls.Certificate) Option {
+	return func(c *config) { c.tls, c.certs = true, append(c.certs, cert) }
+}
+
+// WithToken sends token as bearer token with each request.
+func WithToken(token string) Option { return func(c *config) { c.token = token } }
+
+// WithUnaryInterceptor adds interceptors to unary requests.
+func WithUnaryInterceptor(i ...grpc.UnaryClientInterceptor) Option {
+	return func(c *config) { c.unaryInterceptors = append(c.unaryInterceptors, i...) }
+}
+
+// WithStreamInterceptor adds interceptors to streaming requests.
+func WithStreamInterceptor(i ...grpc.StreamClientInterceptor) Option {
+	return func(c *config) { c.streamInterceptors = append(c.streamInterceptors, i...) }
+}
+
+// WithDialOptions passes options (keepalive, max message size, ...) to grpc.Dial.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *config) { c.dialOptions = append(c.dialOptions, opts...) }
+}
+
+func (c *config) grpcDialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+	if c.tls {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			Certificates: c.certs,
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      c.rootCAs,
+		})))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if c.token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: c.token, secure: c.tls}))
+	}
+	if len(c.unaryInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(c.unaryInterceptors...))
+	}
+	if len(c.streamInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(c.streamInterceptors...))
+	}
+	return append(opts, c.dialOptions...)
+}
+
+// Dial connects to the server at target.
+func Dial(target string, opts ...Option) (*Client, error) {
+	c := new(config)
+	for _, opt := range opts {
+		opt(c)
+	}
+	conn, err := grpc.Dial(target, c.grpcDialOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	return New(conn), nil
+}
+
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+// RequireTransportSecurity allows tokens over insecure connections only if TLS isn't configured.
+func (t tokenCredentials) RequireTransportSecurity() bool { return t.secure }
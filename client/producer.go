@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jney/airq"
+	"github.com/rs/xid"
+)
+
+// ErrClosed is returned when pushing to a closed Producer.
+var ErrClosed = errors.New("producer closed")
+
+// ProducerOptions configures a Producer.
+type ProducerOptions struct {
+	// BatchSize is the maximum count of jobs pushed at once, 100 by default.
+	BatchSize int
+	// FlushInterval is the maximum time a job is buffered, 100ms by default.
+	FlushInterval time.Duration
+	// MaxRetries is the count of retries of a batch while the server is
+	// unavailable, 5 by default, none if negative.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on each retry, 100ms by default.
+	Backoff time.Duration
+	// OnError is called with the jobs that couldn't be pushed. Errors are
+	// returned by Close if not set.
+	OnError func(jobs []*airq.Job, err error)
+}
+
+// Producer buffers pushed jobs and sends them in batches, by size or time.
+// Safe for concurrent use.
+type Producer struct {
+	c       *Client
+	opts    ProducerOptions
+	batches chan []*airq.Job
+	stopped chan struct{}
+
+	mu      sync.Mutex
+	buf     []*airq.Job
+	closed  bool
+	err     error
+	pushing sync.WaitGroup
+}
+
+// NewProducer returns a producer pushing jobs with c, to be closed to flush buffered jobs.
+func NewProducer(c *Client, opts *ProducerOptions) *Producer {
+	p := &Producer{
+		c:       c,
+		batches: make(chan []*airq.Job),
+		stopped: make(chan struct{}),
+	}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.BatchSize <= 0 {
+		p.opts.BatchSize = 100
+	}
+	if p.opts.FlushInterval <= 0 {
+		p.opts.FlushInterval = 100 * time.Millisecond
+	}
+	if p.opts.MaxRetries == 0 {
+		p.opts.MaxRetries = 5
+	}
+	if p.opts.Backoff <= 0 {
+		p.opts.Backoff = 100 * time.Millisecond
+	}
+	go p.run()
+	return p
+}
+
+// Push buffers jobs, blocking while a full batch is being sent. Jobs created
+// with CreateStrategy without id are given one, so that retries don't push
+// them twice.
+func (p *Producer) Push(jobs ...*airq.Job) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	for _, j := range jobs {
+		if j.ID == "" && j.Strategy == airq.CreateStrategy {
+			j.ID = xid.New().String()
+		}
+	}
+	p.buf = append(p.buf, jobs...)
+	var batches [][]*airq.Job
+	for len(p.buf) >= p.opts.BatchSize {
+		batches = append(batches, p.buf[:p.opts.BatchSize:p.opts.BatchSize])
+		p.buf = p.buf[p.opts.BatchSize:]
+	}
+	p.pushing.Add(1)
+	p.mu.Unlock()
+	defer p.pushing.Done()
+
+	for _, batch := range batches {
+		p.batches <- batch
+	}
+	return nil
+}
+
+// Close sends the buffered jobs and waits for pending batches. It returns the
+// errors of the batches that couldn't be pushed, if ProducerOptions.OnError is not set.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.pushing.Wait()
+	p.mu.Lock()
+	batch := p.take()
+	p.mu.Unlock()
+	if len(batch) > 0 {
+		p.batches <- batch
+	}
+	close(p.batches)
+	<-p.stopped
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *Producer) run() {
+	defer close(p.stopped)
+	t := time.NewTicker(p.opts.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case batch, ok := <-p.batches:
+			if !ok {
+				return
+			}
+			p.send(batch)
+		case <-t.C:
+			p.mu.Lock()
+			batch := p.take()
+			p.mu.Unlock()
+			if len(batch) > 0 {
+				p.send(batch)
+			}
+		}
+	}
+}
+
+// take empties the buffer, the lock being held.
+func (p *Producer) take() []*airq.Job {
+	batch := p.buf
+	p.buf = nil
+	return batch
+}
+
+// send pushes a batch, retrying with backoff while the server is unavailable.
+func (p *Producer) send(batch []*airq.Job) {
+	delay := p.opts.Backoff
+	var err error
+	for i := 0; ; i++ {
+		if _, err = p.c.Push(context.Background(), batch...); !errors.Is(err, airq.ErrUnavailable) || i >= p.opts.MaxRetries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	if err == nil {
+		return
+	}
+	if p.opts.OnError != nil {
+		p.opts.OnError(batch, err)
+		return
+	}
+	p.mu.Lock()
+	p.err = multierror.Append(p.err, err)
+	p.mu.Unlock()
+}
@@ -0,0 +1,322 @@
+package airq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ConnProvider provides the connections of a queue, e.g. a Redis Cluster
+// client routing each key to the node serving its slot.
+type ConnProvider interface {
+	// Get returns a connection to the node serving key, closed by the caller.
+	// As with redis.Pool, errors are returned by the connection.
+	Get(key string) redis.Conn
+}
+
+// WithConnProvider gets the connections of the queue from p, taking precedence
+// over WithPool and WithConn.
+func WithConnProvider(p ConnProvider) Option { return func(q *Queue) { q.provider = p } }
+
+// WithCluster runs the queue on a Redis Cluster. The queue name is a hash tag
+// (see WithPrefix) so that all the keys of the queue are in the same slot.
+func WithCluster(c *Cluster) Option {
+	return func(q *Queue) { q.provider, q.hashTag = c, true }
+}
+
+const (
+	clusterSlots  = 16384
+	maxRedirects  = 5
+	defaultIdle   = 8
+	refreshPeriod = time.Second
+)
+
+// Cluster is a ConnProvider for Redis Cluster. It discovers the slots of the
+// nodes, keeps a pool by node and follows MOVED and ASK redirections of Do.
+// The slots are reloaded after MOVED redirections and connection errors, e.g.
+// once a replica is promoted, the failed command not being retried.
+type Cluster struct {
+	// Addrs are the addresses of the nodes used to discover the cluster.
+	Addrs []string
+	// Dial connects to a node, redis.Dial("tcp", addr) if nil.
+	Dial func(addr string) (redis.Conn, error)
+	// MaxIdle, MaxActive and IdleTimeout configure the pool of each node,
+	// MaxIdle being 8 if 0.
+	MaxIdle     int
+	MaxActive   int
+	IdleTimeout time.Duration
+
+	mu        sync.RWMutex
+	pools     map[string]*redis.Pool
+	refreshMu sync.Mutex
+	refreshed time.Time
+	slots     []string // node address by slot, nil until discovered
+}
+
+// Get returns a connection to the node serving key.
+func (c *Cluster) Get(key string) redis.Conn {
+	addr, err := c.nodeOf(key)
+	if err != nil {
+		return errorConn{err}
+	}
+	return &clusterConn{cluster: c, Conn: c.pool(addr).Get()}
+}
+
+// Close closes the pools of the nodes.
+func (c *Cluster) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var mErr error
+	for addr, p := range c.pools {
+		if err := p.Close(); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+		delete(c.pools, addr)
+	}
+	return mErr
+}
+
+func (c *Cluster) nodeOf(key string) (string, error) {
+	c.mu.RLock()
+	slots := c.slots
+	c.mu.RUnlock()
+	if slots == nil {
+		if err := c.Refresh(); err != nil {
+			return "", err
+		}
+		c.mu.RLock()
+		slots = c.slots
+		c.mu.RUnlock()
+	}
+	if addr := slots[keySlot(key)]; addr != "" {
+		return addr, nil
+	}
+	return "", fmt.Errorf("%w: slot %d of key %s not served", ErrUnavailable, keySlot(key), key)
+}
+
+// Refresh reloads the slots of the nodes, from the first node answering.
+// Refreshes are at most once a second, MOVED redirections and connection
+// errors of Do triggering them.
+func (c *Cluster) Refresh() error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.mu.RLock()
+	fresh := c.slots != nil && time.Since(c.refreshed) < refreshPeriod
+	addrs := append([]string(nil), c.Addrs...)
+	for addr := range c.pools {
+		if !contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+	c.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+	var mErr error
+	for _, addr := range addrs {
+		slots, err := c.loadSlots(addr)
+		if err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+		c.mu.Lock()
+		c.slots, c.refreshed = slots, time.Now()
+		c.mu.Unlock()
+		return nil
+	}
+	if mErr == nil {
+		return fmt.Errorf("%w: no cluster address", ErrUnavailable)
+	}
+	return fmt.Errorf("%w: %v", ErrUnavailable, mErr)
+}
+
+// loadSlots returns the node address by slot, as given by addr.
+func (c *Cluster) loadSlots(addr string) ([]string, error) {
+	conn := c.pool(addr).Get()
+	defer conn.Close()
+	ranges, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]string, clusterSlots)
+	for _, r := range ranges {
+		// start, end, master [host, port, ...], replicas...
+		info, err := redis.Values(r, nil)
+		if err != nil || len(info) < 3 {
+			return nil, fmt.Errorf("invalid slot range %v", r)
+		}
+		start, err1 := redis.Int(info[0], nil)
+		end, err2 := redis.Int(info[1], nil)
+		node, err3 := redis.Values(info[2], nil)
+		if err1 != nil || err2 != nil || err3 != nil || len(node) < 2 || start < 0 || end >= clusterSlots {
+			return nil, fmt.Errorf("invalid slot range %v", r)
+		}
+		host, _ := redis.String(node[0], nil)
+		port, _ := redis.Int(node[1], nil)
+		if host == "" {
+			host, _, _ = net.SplitHostPort(addr)
+		}
+		for i := start; i <= end; i++ {
+			slots[i] = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+	}
+	return slots, nil
+}
+
+// pool returns the pool of addr, created if missing.
+func (c *Cluster) pool(addr string) *redis.Pool {
+	c.mu.RLock()
+	p, ok := c.pools[addr]
+	c.mu.RUnlock()
+	if ok {
+		return p
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.pools[addr]; ok {
+		return p
+	}
+	if c.pools == nil {
+		c.pools = make(map[string]*redis.Pool)
+	}
+	maxIdle := c.MaxIdle
+	if maxIdle == 0 {
+		maxIdle = defaultIdle
+	}
+	p = &redis.Pool{
+		MaxIdle:     maxIdle,
+		MaxActive:   c.MaxActive,
+		IdleTimeout: c.IdleTimeout,
+		Dial: func() (redis.Conn, error) {
+			if c.Dial != nil {
+				return c.Dial(addr)
+			}
+			return redis.Dial("tcp", addr)
+		},
+	}
+	c.pools[addr] = p
+	return p
+}
+
+// move records that slot is served by addr, after a MOVED redirection.
+func (c *Cluster) move(slot int, addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.slots == nil {
+		return
+	}
+	// slots are read without lock once loaded
+	slots := append([]string(nil), c.slots...)
+	slots[slot] = addr
+	c.slots = slots
+}
+
+// clusterConn is a connection following the redirections of Do. Send, Flush
+// and Receive go to the node of the key given to Get.
+type clusterConn struct {
+	redis.Conn
+	cluster *Cluster
+}
+
+func (c *clusterConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(cmd, args...)
+	for i := 0; i < maxRedirects; i++ {
+		slot, addr, ask, ok := redirection(err)
+		if !ok {
+			break
+		}
+		if ask {
+			// the slot is migrating, only this command goes to addr
+			conn := c.cluster.pool(addr).Get()
+			if _, err = conn.Do("ASKING"); err == nil {
+				reply, err = conn.Do(cmd, args...)
+			}
+			conn.Close()
+			continue
+		}
+		// other slots may have moved too, the redirection is followed
+		// whether the refresh fails or not
+		c.cluster.move(slot, addr)
+		c.cluster.Refresh()
+		c.Conn.Close()
+		c.Conn = c.cluster.pool(addr).Get()
+		reply, err = c.Conn.Do(cmd, args...)
+	}
+	if connError(err) {
+		// the node may have failed over, the next commands going to the
+		// node serving the slot now
+		c.cluster.Refresh()
+	}
+	return reply, err
+}
+
+// connError reports whether err comes from the connection to a node.
+func connError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// redirection parses MOVED and ASK errors, e.g. "MOVED 3999 127.0.0.1:6381".
+func redirection(err error) (slot int, addr string, ask, ok bool) {
+	var rErr redis.Error
+	if !errors.As(err, &rErr) {
+		return 0, "", false, false
+	}
+	f := strings.Fields(string(rErr))
+	if len(f) != 3 || (f[0] != "MOVED" && f[0] != "ASK") {
+		return 0, "", false, false
+	}
+	slot, sErr := strconv.Atoi(f[1])
+	if sErr != nil || slot < 0 || slot >= clusterSlots {
+		return 0, "", false, false
+	}
+	return slot, f[2], f[0] == "ASK", true
+}
+
+// errorConn is the connection returned when none can be provided.
+type errorConn struct{ err error }
+
+func (c errorConn) Close() error                                   { return nil }
+func (c errorConn) Err() error                                     { return c.err }
+func (c errorConn) Do(string, ...interface{}) (interface{}, error) { return nil, c.err }
+func (c errorConn) Send(string, ...interface{}) error              { return c.err }
+func (c errorConn) Flush() error                                   { return c.err }
+func (c errorConn) Receive() (interface{}, error)                  { return nil, c.err }
+
+// keySlot returns the cluster slot of key, the CRC16 of its hash tag if any.
+func keySlot(key string) int {
+	if i := strings.IndexByte(key, '{'); i >= 0 {
+		if j := strings.IndexByte(key[i+1:], '}'); j > 0 {
+			key = key[i+1 : i+1+j]
+		}
+	}
+	var crc uint16
+	for i := 0; i < len(key); i++ {
+		crc ^= uint16(key[i]) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return int(crc) % clusterSlots
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package airq
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestKeySlot(t *testing.T) {
+	for key, slot := range map[string]int{
+		"123456789":       12739,
+		"{123456789}:abc": 12739,
+		"foo":             12182,
+		"app:{emails}":    3728,
+		"{}emails":        1624, // empty tags are not hash tags
+	} {
+		if s := keySlot(key); s != slot {
+			t.Error("Expected slot", slot, "for", key, "got", s)
+		}
+	}
+	if keySlot("{emails}") != keySlot("{emails}:values") {
+		t.Error("Expected the keys of a queue in the same slot")
+	}
+}
+
+func TestCluster(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	cluster := &Cluster{Addrs: []string{"127.0.0.1:6379"}}
+	defer cluster.Close()
+	WithCluster(cluster)(q)
+
+	addJobs(t, q, Job{Content: "reserved"}, Job{Content: "popped"})
+	jobs, err := q.Reserve(1, time.Minute)
+	if err != nil || len(jobs) != 1 {
+		t.Error("Expected a reserved job, got", jobs, err)
+		t.FailNow()
+	}
+	if err := q.Ack(jobs[0].ID); err != nil {
+		t.Error(err)
+	}
+	if job, err := q.Pop(); err != nil || job == nil || job.Content != "popped" {
+		t.Error("Expected popped, got", job, err)
+	}
+	if ok, _ := redis.Bool(q.conn.Do("SISMEMBER", "airq:queues", q.Name)); !ok {
+		t.Error("Expected", q.Name, "to be registered")
+	}
+	infos, err := ListQueues(nil, WithCluster(cluster))
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, info := range infos {
+		found = found || info.Name == q.Name
+	}
+	if !found {
+		t.Error("Expected", q.Name, "to be listed, got", infos)
+	}
+}
+
+// movedConn redirects every command to addr.
+type movedConn struct {
+	errorConn
+	addr string
+}
+
+func (c movedConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	key, _ := redis.String(args[0], nil)
+	return nil, redis.Error(fmt.Sprintf("MOVED %d %s", keySlot(key), c.addr))
+}
+
+func TestClusterRedirection(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	cluster := &Cluster{Dial: func(addr string) (redis.Conn, error) {
+		if addr == "moved:6379" {
+			return movedConn{addr: "127.0.0.1:6379"}, nil
+		}
+		return redis.Dial("tcp", addr)
+	}}
+	defer cluster.Close()
+	// stale slots, as after a resharding
+	cluster.slots = make([]string, clusterSlots)
+	for i := range cluster.slots {
+		cluster.slots[i] = "moved:6379"
+	}
+	// the redirection is followed even if refreshes are rate limited
+	cluster.refreshed = time.Now()
+	WithCluster(cluster)(q)
+
+	addJobs(t, q, Job{Content: "job"})
+	if pending, err := q.Pending(); err != nil || pending != 1 {
+		t.Error("Expected 1 job pending, got", pending, err)
+	}
+	if addr, _ := cluster.nodeOf(q.key()); addr != "127.0.0.1:6379" {
+		t.Error("Expected the slot to be moved, got", addr)
+	}
+}
+
+func TestClusterFailover(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	cluster := &Cluster{Addrs: []string{"127.0.0.1:6379"}, Dial: func(addr string) (redis.Conn, error) {
+		if addr == "failed:6379" {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return redis.Dial("tcp", addr)
+	}}
+	defer cluster.Close()
+	// slots of a master that failed, a replica being promoted
+	cluster.slots = make([]string, clusterSlots)
+	for i := range cluster.slots {
+		cluster.slots[i] = "failed:6379"
+	}
+	cluster.refreshed = time.Now().Add(-time.Minute)
+	WithCluster(cluster)(q)
+
+	if _, err := q.Pending(); err == nil {
+		t.Error("Expected the command to fail on the failed node")
+	}
+	if addr, _ := cluster.nodeOf(q.key()); addr == "failed:6379" {
+		t.Error("Expected the slots to be refreshed, got", addr)
+	}
+	if _, err := q.Pending(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/server"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the server configuration, read from a YAML file and overridden by
+// AIRQ_* variables. ${VAR} is replaced by the environment variable VAR in the
+// addresses, the redis URL, the TLS files and the auth tokens.
+type config struct {
+	// Listen is the gRPC address, a unix socket path if prefixed by "unix:".
+	Listen string `yaml:"listen"`
+	// HealthListen is the address of the HTTP health checks, disabled if empty.
+	HealthListen string        `yaml:"health_listen"`
+	DrainTimeout time.Duration `yaml:"drain_timeout"`
+	MaxBatchSize int           `yaml:"max_batch_size"`
+	MaxJobSize   int           `yaml:"max_job_size"`
+	// Queues are the queues served, a single one being the default queue of
+	// requests. Any queue is served if empty.
+	Queues []string    `yaml:"queues"`
+	Queue  queueConfig `yaml:"queue"`
+	Redis  redisConfig `yaml:"redis"`
+	TLS    tlsConfig   `yaml:"tls"`
+	Auth   authConfig  `yaml:"auth"`
+}
+
+type queueConfig struct {
+	// Prefix enables the hash-tagged key layout, e.g. "app:" for app:{name}.
+	Prefix         *string `yaml:"prefix"`
+	MaxPayloadSize int     `yaml:"max_payload_size"`
+	// OffloadThreshold is the size above which jobs are stored under their own key.
+	OffloadThreshold int `yaml:"offload_threshold"`
+}
+
+type redisConfig struct {
+	// URL is the redis URL, e.g. redis://:password@127.0.0.1:6379/0.
+	URL         string        `yaml:"url"`
+	MaxIdle     int           `yaml:"max_idle"`
+	MaxActive   int           `yaml:"max_active"`
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	Wait        bool          `yaml:"wait"`
+}
+
+type tlsConfig struct {
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+	// ClientCA requires client certificates signed by the CA of this file.
+	ClientCA string `yaml:"client_ca"`
+}
+
+type authConfig struct {
+	// Tokens are the bearer tokens accepted, with their grant. Authorization
+	// is disabled if empty.
+	Tokens map[string]grant `yaml:"tokens"`
+}
+
+type grant struct {
+	Queues     []string `yaml:"queues"`
+	Operations []string `yaml:"operations"`
+}
+
+func defaultConfig() *config {
+	return &config{
+		Listen:       ":4242",
+		DrainTimeout: 30 * time.Second,
+		MaxBatchSize: server.DefaultMaxBatchSize,
+		Redis: redisConfig{
+			URL:         "redis://127.0.0.1:6379",
+			MaxIdle:     8,
+			IdleTimeout: 5 * time.Minute,
+		},
+	}
+}
+
+// loadConfig reads the file at path if not empty, then the environment.
+func loadConfig(path string) (*config, error) {
+	c := defaultConfig()
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if err := c.expand(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	for env, dst := range map[string]*string{
+		"AIRQ_LISTEN":        &c.Listen,
+		"AIRQ_HEALTH_LISTEN": &c.HealthListen,
+		"AIRQ_REDIS_URL":     &c.Redis.URL,
+		"AIRQ_TLS_CERT":      &c.TLS.Cert,
+		"AIRQ_TLS_KEY":       &c.TLS.Key,
+		"AIRQ_TLS_CLIENT_CA": &c.TLS.ClientCA,
+	} {
+		if v, ok := os.LookupEnv(env); ok {
+			*dst = v
+		}
+	}
+	if v, ok := os.LookupEnv("AIRQ_DRAIN_TIMEOUT"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("AIRQ_DRAIN_TIMEOUT: %w", err)
+		}
+		c.DrainTimeout = d
+	}
+	if v, ok := os.LookupEnv("AIRQ_REDIS_MAX_ACTIVE"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("AIRQ_REDIS_MAX_ACTIVE: %w", err)
+		}
+		c.Redis.MaxActive = n
+	}
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		return nil, fmt.Errorf("tls: cert and key must be set together")
+	}
+	if c.TLS.ClientCA != "" && c.TLS.Cert == "" {
+		return nil, fmt.Errorf("tls: client_ca requires cert and key")
+	}
+	return c, nil
+}
+
+// envVar matches the ${VAR} references of the configuration.
+var envVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expand replaces the ${VAR} references of the fields holding secrets and
+// environment specific values, other fields being kept as is.
+func (c *config) expand() error {
+	var missing []string
+	expand := func(s string) string {
+		return envVar.ReplaceAllStringFunc(s, func(ref string) string {
+			name := envVar.FindStringSubmatch(ref)[1]
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+	}
+	for _, s := range []*string{&c.Listen, &c.HealthListen, &c.Redis.URL, &c.TLS.Cert, &c.TLS.Key, &c.TLS.ClientCA} {
+		*s = expand(*s)
+	}
+	if len(c.Auth.Tokens) > 0 {
+		tokens := make(map[string]grant, len(c.Auth.Tokens))
+		for token, g := range c.Auth.Tokens {
+			tokens[expand(token)] = g
+		}
+		c.Auth.Tokens = tokens
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (c *config) pool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     c.Redis.MaxIdle,
+		MaxActive:   c.Redis.MaxActive,
+		IdleTimeout: c.Redis.IdleTimeout,
+		Wait:        c.Redis.Wait,
+		Dial:        func() (redis.Conn, error) { return redis.DialURL(c.Redis.URL) },
+		TestOnBorrow: func(conn redis.Conn, t time.Time) error {
+			if time.Since(t) < time.Minute {
+				return nil
+			}
+			_, err := conn.Do("PING")
+			return err
+		},
+	}
+}
+
+func (c *config) queueOptions() []airq.Option {
+	var opts []airq.Option
+	if c.Queue.Prefix != nil {
+		opts = append(opts, airq.WithPrefix(*c.Queue.Prefix))
+	}
+	if c.Queue.MaxPayloadSize > 0 {
+		opts = append(opts, airq.WithMaxPayloadSize(c.Queue.MaxPayloadSize))
+	}
+	if c.Queue.OffloadThreshold > 0 {
+		opts = append(opts, airq.WithOffload(c.Queue.OffloadThreshold))
+	}
+	return opts
+}
+
+func (c *config) serverOptions() ([]server.Option, error) {
+	opts := []server.Option{
+		server.WithDrainTimeout(c.DrainTimeout),
+		server.WithMaxBatchSize(c.MaxBatchSize),
+		server.WithMaxJobSize(c.MaxJobSize),
+	}
+	if c.TLS.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLS.Cert, c.TLS.Key)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		opts = append(opts, server.WithTLS(cert))
+	}
+	if c.TLS.ClientCA != "" {
+		b, err := os.ReadFile(c.TLS.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("tls: no certificate found in %s", c.TLS.ClientCA)
+		}
+		opts = append(opts, server.WithClientCAs(pool))
+	}
+	if len(c.Auth.Tokens) > 0 {
+		auth := make(server.TokenAuth, len(c.Auth.Tokens))
+		for token, g := range c.Auth.Tokens {
+			auth[token] = server.Grant{Queues: g.Queues, Operations: g.Operations}
+		}
+		opts = append(opts, server.WithAuth(auth))
+	}
+	return opts, nil
+}
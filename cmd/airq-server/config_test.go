@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jney/airq/server"
+)
+
+// writeConfig writes content to a configuration file, returning its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return path
+}
+
+func TestDefaultConfig(t *testing.T) {
+	c, err := loadConfig("")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if c.Listen != ":4242" || c.DrainTimeout != 30*time.Second || c.MaxBatchSize != server.DefaultMaxBatchSize || c.Redis.URL != "redis://127.0.0.1:6379" {
+		t.Error("Expected the default configuration, got", c)
+	}
+	if opts := c.queueOptions(); len(opts) != 0 {
+		t.Error("Expected no queue option, got", len(opts))
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("AIRQ_TEST_PASSWORD", "p@ss")
+	t.Setenv("AIRQ_TEST_TOKEN", "secret")
+	t.Setenv("AIRQ_TEST_PREFIX", "expanded")
+	path := writeConfig(t, `
+listen: ":5000"
+drain_timeout: 5s
+queues: [emails, "$reports"]
+redis:
+  url: redis://:${AIRQ_TEST_PASSWORD}@redis:6379/1
+  max_active: 64
+queue:
+  prefix: "${AIRQ_TEST_PREFIX}:"
+  max_payload_size: 1024
+  offload_threshold: 512
+auth:
+  tokens:
+    ${AIRQ_TEST_TOKEN}:
+      queues: [emails]
+      operations: [Push]
+`)
+	c, err := loadConfig(path)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if c.Listen != ":5000" || c.DrainTimeout != 5*time.Second || c.Redis.MaxActive != 64 || c.Redis.MaxIdle != 8 {
+		t.Error("Expected the file to override the defaults, got", c)
+	}
+	if c.Redis.URL != "redis://:p@ss@redis:6379/1" {
+		t.Error("Expected the redis URL to be expanded, got", c.Redis.URL)
+	}
+	if g, ok := c.Auth.Tokens["secret"]; !ok || g.Queues[0] != "emails" {
+		t.Error("Expected the token to be expanded, got", c.Auth.Tokens)
+	}
+	// other fields are kept as is
+	if *c.Queue.Prefix != "${AIRQ_TEST_PREFIX}:" || c.Queues[1] != "$reports" {
+		t.Error("Expected the queue settings not to be expanded, got", *c.Queue.Prefix, c.Queues)
+	}
+	if opts := c.queueOptions(); len(opts) != 3 {
+		t.Error("Expected 3 queue options, got", len(opts))
+	}
+	if opts, err := c.serverOptions(); err != nil || len(opts) != 4 {
+		t.Error("Expected the auth option, got", len(opts), err)
+	}
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	t.Setenv("AIRQ_LISTEN", "unix:/tmp/airq.sock")
+	t.Setenv("AIRQ_REDIS_URL", "redis://other:6379")
+	t.Setenv("AIRQ_DRAIN_TIMEOUT", "1m")
+	t.Setenv("AIRQ_REDIS_MAX_ACTIVE", "10")
+	c, err := loadConfig(writeConfig(t, "listen: \":5000\"\n"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if c.Listen != "unix:/tmp/airq.sock" || c.Redis.URL != "redis://other:6379" || c.DrainTimeout != time.Minute || c.Redis.MaxActive != 10 {
+		t.Error("Expected the environment to override the file, got", c)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content string
+		env     map[string]string
+		err     string
+	}{
+		"yaml":          {content: "listen: [", err: "server.yaml"},
+		"missing var":   {content: "redis:\n  url: redis://${AIRQ_TEST_MISSING}:6379\n", err: "AIRQ_TEST_MISSING"},
+		"cert only":     {content: "tls:\n  cert: server.pem\n", err: "cert and key"},
+		"client ca":     {content: "tls:\n  client_ca: ca.pem\n", err: "client_ca"},
+		"drain timeout": {env: map[string]string{"AIRQ_DRAIN_TIMEOUT": "soon"}, err: "AIRQ_DRAIN_TIMEOUT"},
+		"max active":    {env: map[string]string{"AIRQ_REDIS_MAX_ACTIVE": "many"}, err: "AIRQ_REDIS_MAX_ACTIVE"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			_, err := loadConfig(writeConfig(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected an error about %s, got %v", tc.err, err)
+			}
+		})
+	}
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
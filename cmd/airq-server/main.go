@@ -0,0 +1,145 @@
+// Command airq-server serves queues over gRPC, configured by a YAML file and
+// the environment (see config). It drains pending requests on SIGTERM and
+// exposes health checks on the standard gRPC health service and, if
+// health_listen is set, over HTTP on /healthz (liveness) and /readyz (redis
+// reachable and not draining).
+//
+//	airq-server -config /etc/airq/server.yaml
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/server"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// checkInterval is the delay between two checks of redis.
+const checkInterval = 5 * time.Second
+
+func main() {
+	path := flag.String("config", os.Getenv("AIRQ_CONFIG"), "configuration file (YAML)")
+	flag.Parse()
+	if err := run(*path); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(path string) error {
+	c, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	opts, err := c.serverOptions()
+	if err != nil {
+		return err
+	}
+	pool := c.pool()
+	defer pool.Close()
+
+	var srv server.Server
+	if len(c.Queues) == 1 {
+		q := airq.New(c.Queues[0], append([]airq.Option{airq.WithPool(pool)}, c.queueOptions()...)...)
+		srv = server.New(q, opts...)
+	} else {
+		srv = server.NewMulti(pool, c.Queues, c.queueOptions(), opts...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	h := &healthCheck{grpc: health.NewServer()}
+	healthpb.RegisterHealthServer(srv.Server, h.grpc)
+	go h.watch(ctx, pool)
+	if c.HealthListen != "" {
+		hs := &http.Server{Addr: c.HealthListen, Handler: h}
+		go func() {
+			if err := hs.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Print("health checks: ", err)
+			}
+		}()
+		defer hs.Close()
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(c.Listen) }()
+	log.Printf("serving %d queue(s) on %s", len(c.Queues), c.Listen)
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	log.Printf("draining, for at most %s", c.DrainTimeout)
+	h.shutdown()
+	srv.GracefulStop()
+	return nil
+}
+
+// healthCheck reports the server as serving while redis answers.
+type healthCheck struct {
+	grpc  *health.Server
+	ready int32
+}
+
+func (h *healthCheck) watch(ctx context.Context, pool *redis.Pool) {
+	t := time.NewTicker(checkInterval)
+	defer t.Stop()
+	for {
+		h.check(pool)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (h *healthCheck) check(pool *redis.Pool) {
+	c := pool.Get()
+	defer c.Close()
+	status := healthpb.HealthCheckResponse_SERVING
+	if _, err := c.Do("PING"); err != nil {
+		log.Print("redis unavailable: ", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	// ignored once shut down
+	h.grpc.SetServingStatus("", status)
+	h.grpc.SetServingStatus("job.Jobs", status)
+	if status == healthpb.HealthCheckResponse_SERVING {
+		atomic.CompareAndSwapInt32(&h.ready, 0, 1)
+	} else {
+		atomic.CompareAndSwapInt32(&h.ready, 1, 0)
+	}
+}
+
+// shutdown reports the server as not serving for good, while draining.
+func (h *healthCheck) shutdown() {
+	atomic.StoreInt32(&h.ready, -1)
+	h.grpc.Shutdown()
+}
+
+func (h *healthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/healthz":
+		w.WriteHeader(http.StatusOK)
+	case "/readyz":
+		if atomic.LoadInt32(&h.ready) == 1 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/client"
+)
+
+// backend is a queue, in redis or behind a server.
+type backend interface {
+	Push(ctx context.Context, jobs ...*airq.Job) ([]string, error)
+	Pop(ctx context.Context, limit int, visibility time.Duration) ([]*airq.Job, error)
+	Peek(ctx context.Context, limit int) ([]*airq.Job, error)
+	Get(ctx context.Context, id string) (*airq.Job, error)
+	Remove(ctx context.Context, ids ...string) error
+	Ack(ctx context.Context, ids ...string) error
+	Reschedule(ctx context.Context, when time.Time, ids ...string) error
+	Stats(ctx context.Context) (airq.Stats, error)
+	Purge(ctx context.Context) (int64, error)
+}
+
+// redisBackend talks to redis directly.
+type redisBackend struct{ q *airq.Queue }
+
+func (b redisBackend) Push(ctx context.Context, jobs ...*airq.Job) ([]string, error) {
+	return b.q.Push(jobs...)
+}
+
+func (b redisBackend) Pop(ctx context.Context, limit int, visibility time.Duration) ([]*airq.Job, error) {
+	if visibility > 0 {
+		return b.q.Reserve(limit, visibility)
+	}
+	return b.q.PopJobs(limit)
+}
+
+func (b redisBackend) Peek(ctx context.Context, limit int) ([]*airq.Job, error) {
+	return b.q.Peek(limit)
+}
+
+func (b redisBackend) Get(ctx context.Context, id string) (*airq.Job, error) {
+	j, err := b.q.Get(id)
+	if err == nil && j == nil {
+		err = fmt.Errorf("%w: job %s in queue %s", airq.ErrNotFound, id, b.q.Name)
+	}
+	return j, err
+}
+
+func (b redisBackend) Remove(ctx context.Context, ids ...string) error { return b.q.Remove(ids...) }
+
+func (b redisBackend) Ack(ctx context.Context, ids ...string) error { return b.q.Ack(ids...) }
+
+func (b redisBackend) Reschedule(ctx context.Context, when time.Time, ids ...string) error {
+	return b.q.Reschedule(when, ids...)
+}
+
+func (b redisBackend) Stats(ctx context.Context) (airq.Stats, error) { return b.q.Stats() }
+
+func (b redisBackend) Purge(ctx context.Context) (int64, error) { return b.q.Purge() }
+
+// remoteBackend talks to an airq server.
+type remoteBackend struct{ c *client.Client }
+
+func (b remoteBackend) Push(ctx context.Context, jobs ...*airq.Job) ([]string, error) {
+	l, err := b.c.Push(ctx, jobs...)
+	var ids []string
+	for _, id := range l.GetIds() {
+		ids = append(ids, id.GetId())
+	}
+	return ids, err
+}
+
+func (b remoteBackend) Pop(ctx context.Context, limit int, visibility time.Duration) ([]*airq.Job, error) {
+	if visibility > 0 {
+		return b.c.Reserve(ctx, limit, visibility)
+	}
+	return b.c.Pop(ctx, limit)
+}
+
+func (b remoteBackend) Peek(ctx context.Context, limit int) ([]*airq.Job, error) {
+	return b.c.Peek(ctx, limit)
+}
+
+func (b remoteBackend) Get(ctx context.Context, id string) (*airq.Job, error) {
+	return b.c.Get(ctx, id)
+}
+
+func (b remoteBackend) Remove(ctx context.Context, ids ...string) error {
+	return b.c.Remove(ctx, ids...)
+}
+
+func (b remoteBackend) Ack(ctx context.Context, ids ...string) error { return b.c.Ack(ctx, ids...) }
+
+func (b remoteBackend) Reschedule(ctx context.Context, when time.Time, ids ...string) error {
+	return b.c.Reschedule(ctx, when, ids...)
+}
+
+func (b remoteBackend) Stats(ctx context.Context) (airq.Stats, error) { return b.c.Stats(ctx) }
+
+func (b remoteBackend) Purge(ctx context.Context) (int64, error) { return b.c.Purge(ctx) }
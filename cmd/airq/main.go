@@ -0,0 +1,505 @@
+// Command airq operates queues, in redis or through an airq server, printing
+// results as JSON.
+//
+//	airq -queue emails push -in 10m -subject email "hello"
+//	airq -queue emails peek -n 20
+//	airq -queue emails -server airq-server:4242 -token secret stats
+//	airq -queue emails requeue -from emails-dead
+//	airq -queue emails -keys k2=$NEW_KEY,k1=$OLD_KEY -blob-dir /var/lib/airq pop
+//
+// Jobs of queues encrypted or offloaded to a blob store are read with the same
+// -keys, -offload and -blob-dir, popped jobs that can't be decoded being
+// pushed back a minute later.
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/client"
+	"github.com/jney/airq/gateway"
+)
+
+// errUsage reports invalid arguments, the usage being printed.
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, e env, b backend, args []string) (interface{}, error)
+	// global commands run without queue
+	global bool
+}
+
+var commands = map[string]command{
+	"push":       {"push [-when time | -in duration] [-id id] [-subject subject] [-header key=value] [-create] content... (- reads stdin)", push, false},
+	"pop":        {"pop [-n limit] [-visibility duration]", pop, false},
+	"peek":       {"peek [-n limit]", peek, false},
+	"get":        {"get id", get, false},
+	"remove":     {"remove id...", remove, false},
+	"reschedule": {"reschedule [-when time | -in duration] id...", reschedule, false},
+	"stats":      {"stats", stats, false},
+	"purge":      {"purge -yes [-unregister]", purge, false},
+	"requeue":    {"requeue -from dead-letter-queue [-n limit]", requeue, false},
+	"queues":     {"queues", queues, true},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command of args and returns the exit code, 2 for invalid usage.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("airq", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	queue := fs.String("queue", os.Getenv("AIRQ_QUEUE"), "queue name, the server default queue if empty")
+	namespace := fs.String("namespace", os.Getenv("AIRQ_NAMESPACE"), "namespace the queues are registered in")
+	prefix := fs.String("prefix", os.Getenv("AIRQ_PREFIX"), "key prefix, the queue names being hash tags if set")
+	redisURL := fs.String("redis", envOr("AIRQ_REDIS", "redis://127.0.0.1:6379"), "redis URL")
+	server := fs.String("server", os.Getenv("AIRQ_SERVER"), "airq server address, redis is used if empty")
+	token := fs.String("token", os.Getenv("AIRQ_TOKEN"), "airq server token")
+	ca := fs.String("ca", "", "CA certificate file of the airq server, TLS being disabled if empty")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of the command, and of each redis connection and command")
+	keys := fs.String("keys", os.Getenv("AIRQ_KEYS"), "encryption keys as id=base64,..., the first one encrypting new jobs")
+	offload := fs.Int("offload", 0, "size above which jobs are offloaded, under their own redis key unless -blob-dir is set")
+	blobDir := fs.String("blob-dir", os.Getenv("AIRQ_BLOB_DIR"), "directory of the offloaded jobs")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: airq [flags] command [args]\n\ncommands:")
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintln(fs.Output(), "  "+commands[name].usage)
+		}
+		fmt.Fprintln(fs.Output(), "\nflags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return 2
+	}
+
+	e, err := newEnv(*redisURL, *server, *token, *ca, *timeout)
+	if err != nil {
+		return exit(stderr, err)
+	}
+	defer e.close()
+	if *queue == "" && e.cli == nil && !cmd.global {
+		return exit(stderr, fmt.Errorf("%w: -queue is required without -server", errUsage))
+	}
+	e.queue, e.stdin = *queue, stdin
+	if *namespace != "" {
+		e.queueOpts = append(e.queueOpts, airq.WithNamespace(*namespace))
+	}
+	if *prefix != "" {
+		e.queueOpts = append(e.queueOpts, airq.WithPrefix(*prefix))
+	}
+	if opts, err := storageOptions(*keys, *offload, *blobDir); err != nil {
+		return exit(stderr, err)
+	} else if len(opts) > 0 {
+		if e.cli != nil {
+			return exit(stderr, fmt.Errorf("%w: -keys, -offload and -blob-dir are set on the server", errUsage))
+		}
+		e.queueOpts = append(e.queueOpts, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	res, err := cmd.run(ctx, e, e.backend(e.queue), fs.Args()[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(stderr, "usage: airq [flags]", cmd.usage)
+	}
+	// results are printed along with errors, e.g. jobs popped before a failure
+	if res != nil {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if eErr := enc.Encode(res); err == nil {
+			err = eErr
+		}
+	}
+	if err != nil {
+		return exit(stderr, err)
+	}
+	return 0
+}
+
+// storageOptions returns the queue options reading encrypted and offloaded
+// jobs: keys are id=base64 pairs separated by commas, the first one being the
+// current key.
+func storageOptions(keys string, offload int, blobDir string) ([]airq.Option, error) {
+	var opts []airq.Option
+	if keys != "" {
+		var current string
+		ring := make(map[string][]byte)
+		for _, kv := range strings.Split(keys, ",") {
+			id, v, ok := strings.Cut(kv, "=")
+			if !ok || id == "" {
+				return nil, fmt.Errorf("%w: key %q is not id=base64", errUsage, id)
+			}
+			key, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: key %s: %v", errUsage, id, err)
+			}
+			if current == "" {
+				current = id
+			}
+			ring[id] = key
+		}
+		r, err := airq.NewKeyRing(current, ring)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		opts = append(opts, airq.WithEncryption(r))
+	}
+	switch {
+	case blobDir != "":
+		s, err := airq.NewFileBlobStore(blobDir)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, airq.WithBlobStore(s, offload))
+	case offload > 0:
+		opts = append(opts, airq.WithOffload(offload))
+	}
+	return opts, nil
+}
+
+// env holds the connection to redis or to the server.
+type env struct {
+	cli       *client.Client
+	pool      *redis.Pool
+	queue     string
+	queueOpts []airq.Option
+	stdin     io.Reader
+}
+
+// newEnv connects to redis, or to server if set, redis commands failing after
+// timeout as the requests to the server.
+func newEnv(redisURL, server, token, ca string, timeout time.Duration) (env, error) {
+	if server == "" {
+		return env{pool: &redis.Pool{
+			MaxIdle: 2,
+			Dial: func() (redis.Conn, error) {
+				return redis.DialURL(redisURL,
+					redis.DialConnectTimeout(timeout),
+					redis.DialReadTimeout(timeout),
+					redis.DialWriteTimeout(timeout),
+				)
+			},
+		}}, nil
+	}
+	var opts []client.Option
+	if ca != "" {
+		b, err := os.ReadFile(ca)
+		if err != nil {
+			return env{}, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return env{}, fmt.Errorf("no certificate found in %s", ca)
+		}
+		opts = append(opts, client.WithTLS(pool))
+	}
+	if token != "" {
+		opts = append(opts, client.WithToken(token))
+	}
+	cli, err := client.Dial(server, opts...)
+	return env{cli: cli}, err
+}
+
+func (e env) backend(queue string) backend {
+	if e.cli != nil {
+		return remoteBackend{e.cli.WithQueue(queue)}
+	}
+	return redisBackend{airq.New(queue, append([]airq.Option{airq.WithPool(e.pool)}, e.queueOpts...)...)}
+}
+
+func (e env) close() {
+	if e.cli != nil {
+		e.cli.Close()
+	}
+	if e.pool != nil {
+		e.pool.Close()
+	}
+}
+
+func push(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("push", flag.ContinueOnError)
+	when := whenFlags(fs)
+	id := fs.String("id", "", "job id, generated if empty")
+	subject := fs.String("subject", "", "job subject")
+	create := fs.Bool("create", false, "create a new job even if the same content is pending")
+	headers := headerFlag{}
+	fs.Var(headers, "header", "job header as key=value, repeatable")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() == 0 {
+		return nil, fmt.Errorf("%w: no content provided", errUsage)
+	}
+	if *id != "" && fs.NArg() > 1 {
+		return nil, fmt.Errorf("%w: -id is set for a single job", errUsage)
+	}
+	t, err := when()
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*airq.Job
+	for _, content := range fs.Args() {
+		if content == "-" {
+			b, err := io.ReadAll(e.stdin)
+			if err != nil {
+				return nil, err
+			}
+			content = string(b)
+		}
+		j := &airq.Job{Content: content, ID: *id, Subject: *subject, When: t}
+		if len(headers) > 0 {
+			j.Headers = headers
+		}
+		if *create {
+			j.Strategy = airq.CreateStrategy
+		}
+		jobs = append(jobs, j)
+	}
+	ids, err := b.Push(ctx, jobs...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]string{"ids": ids}, nil
+}
+
+func pop(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("pop", flag.ContinueOnError)
+	n := fs.Int("n", 1, "maximum count of jobs")
+	visibility := fs.Duration("visibility", 0, "reserve jobs until acknowledged instead of removing them")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	jobs, err := b.Pop(ctx, *n, *visibility)
+	// popped jobs are lost if not printed, undecodable ones being pushed back
+	return jobList(jobs), err
+}
+
+func peek(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("peek", flag.ContinueOnError)
+	n := fs.Int("n", 10, "maximum count of jobs")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	jobs, err := b.Peek(ctx, *n)
+	if err != nil {
+		return nil, err
+	}
+	return jobList(jobs), nil
+}
+
+func get(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%w: a single id is expected", errUsage)
+	}
+	j, err := b.Get(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return gateway.FromJob(j), nil
+}
+
+func remove(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: %v", errUsage, airq.ErrNoIDs)
+	}
+	if err := b.Remove(ctx, args...); err != nil {
+		return nil, err
+	}
+	return map[string][]string{"ids": args}, nil
+}
+
+func reschedule(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("reschedule", flag.ContinueOnError)
+	when := whenFlags(fs)
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() == 0 {
+		return nil, fmt.Errorf("%w: %v", errUsage, airq.ErrNoIDs)
+	}
+	t, err := when()
+	if err != nil {
+		return nil, err
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if err := b.Reschedule(ctx, t, fs.Args()...); err != nil {
+		return nil, err
+	}
+	return map[string][]string{"ids": fs.Args()}, nil
+}
+
+func stats(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	return b.Stats(ctx)
+}
+
+func purge(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "confirm all the jobs of the queue are to be deleted")
+	unregister := fs.Bool("unregister", false, "remove the queue from the queues listed")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	if !*yes {
+		return nil, fmt.Errorf("%w: purge deletes all the jobs of the queue, confirm with -yes", errUsage)
+	}
+	rb, ok := b.(redisBackend)
+	if *unregister && !ok {
+		return nil, fmt.Errorf("%w: queues are unregistered from redis, without -server", errUsage)
+	}
+	n, err := b.Purge(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if *unregister {
+		if err := rb.q.Unregister(); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]int64{"purged": n}, nil
+}
+
+// requeue moves the due jobs of a dead-letter queue back to the queue, to be
+// processed right away. Jobs are acknowledged in the dead-letter queue once
+// pushed, they are queued there again if the command fails in between.
+func requeue(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("requeue", flag.ContinueOnError)
+	from := fs.String("from", "", "dead-letter queue name")
+	n := fs.Int("n", 0, "maximum count of jobs, all if 0")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	if *from == "" || *from == e.queue {
+		return nil, fmt.Errorf("%w: -from must name another queue", errUsage)
+	}
+	dead := e.backend(*from)
+	ids := []string{}
+	for *n == 0 || len(ids) < *n {
+		limit := 100
+		if *n > 0 && *n-len(ids) < limit {
+			limit = *n - len(ids)
+		}
+		jobs, err := dead.Pop(ctx, limit, time.Minute)
+		if err != nil || len(jobs) == 0 {
+			return map[string][]string{"ids": ids}, err
+		}
+		var reserved []string
+		for _, j := range jobs {
+			reserved = append(reserved, j.ID)
+			j.When = time.Time{}
+		}
+		if _, err := b.Push(ctx, jobs...); err != nil {
+			return map[string][]string{"ids": ids}, err
+		}
+		if err := dead.Ack(ctx, reserved...); err != nil {
+			return map[string][]string{"ids": ids}, err
+		}
+		ids = append(ids, reserved...)
+	}
+	return map[string][]string{"ids": ids}, nil
+}
+
+// queues lists the queues registered in redis.
+func queues(ctx context.Context, e env, b backend, args []string) (interface{}, error) {
+	if e.pool == nil {
+		return nil, fmt.Errorf("%w: queues are listed from redis, without -server", errUsage)
+	}
+	c := e.pool.Get()
+	defer c.Close()
+	queues, err := airq.ListQueues(c, e.queueOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]airq.QueueInfo{"queues": queues}, nil
+}
+
+// whenFlags defines the -when and -in flags, returning the zero time if none is set.
+func whenFlags(fs *flag.FlagSet) func() (time.Time, error) {
+	when := fs.String("when", "", "execution time (RFC 3339), now if not set")
+	in := fs.Duration("in", 0, "delay before execution")
+	return func() (time.Time, error) {
+		switch {
+		case *when != "" && *in != 0:
+			return time.Time{}, fmt.Errorf("%w: -when and -in are exclusive", errUsage)
+		case *in != 0:
+			return time.Now().Add(*in), nil
+		case *when != "":
+			return time.Parse(time.RFC3339, *when)
+		}
+		return time.Time{}, nil
+	}
+}
+
+// headerFlag collects key=value flags.
+type headerFlag map[string]string
+
+func (h headerFlag) String() string { return "" }
+
+func (h headerFlag) Set(v string) error {
+	k, val, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("header %q is not key=value", v)
+	}
+	h[k] = val
+	return nil
+}
+
+func parse(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+func jobList(jobs []*airq.Job) map[string][]gateway.Job {
+	res := make([]gateway.Job, len(jobs))
+	for i, j := range jobs {
+		res[i] = gateway.FromJob(j)
+	}
+	return map[string][]gateway.Job{"jobs": res}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// exit prints err and returns the exit code.
+func exit(stderr io.Writer, err error) int {
+	fmt.Fprintln(stderr, "airq:", err)
+	if errors.Is(err, errUsage) {
+		return 2
+	}
+	return 1
+}
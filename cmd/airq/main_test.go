@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+)
+
+// cli runs commands against a random queue of a random namespace.
+type cli struct {
+	t     *testing.T
+	flags []string
+}
+
+func setup(t *testing.T) (*cli, func()) {
+	t.Parallel()
+	name, ns := randomName(), randomName()
+	c := &cli{t: t, flags: []string{"-redis", "redis://127.0.0.1:6379", "-queue", name, "-namespace", ns}}
+	teardown := func() {
+		conn, err := redis.Dial("tcp", "127.0.0.1:6379")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		keys, _ := redis.Strings(conn.Do("KEYS", name+"*"))
+		for _, k := range append(keys, "airq:queues:"+ns) {
+			conn.Do("DEL", k)
+		}
+	}
+	return c, teardown
+}
+
+// run runs args after the flags of the queue, returning the exit code and the
+// output.
+func (c *cli) run(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append(append([]string{}, c.flags...), args...), strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// decode runs args, expecting them to succeed, and decodes the output in v.
+func (c *cli) decode(v interface{}, args ...string) {
+	c.t.Helper()
+	code, out, errOut := c.run("", args...)
+	if code != 0 {
+		c.t.Error("Expected", args, "to succeed, got", code, errOut)
+		c.t.FailNow()
+	}
+	if err := json.Unmarshal([]byte(out), v); err != nil {
+		c.t.Error(err, out)
+	}
+}
+
+func randomName() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+type jobsOutput struct {
+	Jobs []struct {
+		ID      string            `json:"id"`
+		Content string            `json:"content"`
+		Subject string            `json:"subject"`
+		Headers map[string]string `json:"headers"`
+	} `json:"jobs"`
+}
+
+func TestCommands(t *testing.T) {
+	c, teardown := setup(t)
+	defer teardown()
+
+	var pushed struct{ IDs []string }
+	c.decode(&pushed, "push", "-id", "01", "-subject", "email", "-header", "to=jane", "hello")
+	if len(pushed.IDs) != 1 || pushed.IDs[0] != "01" {
+		t.Error("Expected to push job 01, got", pushed.IDs)
+	}
+	code, out, _ := c.run("from stdin", "push", "-in", "1h", "-")
+	if code != 0 || !strings.Contains(out, "ids") {
+		t.Error("Expected to push from stdin, got", code, out)
+	}
+
+	var peeked jobsOutput
+	c.decode(&peeked, "peek")
+	if len(peeked.Jobs) != 2 || peeked.Jobs[0].Subject != "email" || peeked.Jobs[0].Headers["to"] != "jane" || peeked.Jobs[1].Content != "from stdin" {
+		t.Error("Expected the pushed jobs, got", peeked.Jobs)
+	}
+	var got struct{ Content string }
+	c.decode(&got, "get", "01")
+	if got.Content != "hello" {
+		t.Error("Expected job 01, got", got)
+	}
+
+	var stats airq.Stats
+	c.decode(&stats, "stats")
+	if stats.Pending != 2 || stats.Due != 1 {
+		t.Error("Expected 2 jobs pending and 1 due, got", stats)
+	}
+
+	c.decode(&pushed, "reschedule", peeked.Jobs[1].ID)
+	var popped jobsOutput
+	c.decode(&popped, "pop", "-n", "10")
+	if len(popped.Jobs) != 2 {
+		t.Error("Expected the rescheduled job to be popped, got", popped.Jobs)
+	}
+
+	c.decode(&pushed, "push", "-id", "02", "removed")
+	c.decode(&pushed, "remove", "02")
+	c.decode(&pushed, "push", "purged")
+	var purged struct{ Purged int64 }
+	c.decode(&purged, "purge", "-yes")
+	if purged.Purged != 1 {
+		t.Error("Expected 1 job purged, got", purged.Purged)
+	}
+
+	var queues struct{ Queues []airq.QueueInfo }
+	c.decode(&queues, "queues")
+	if len(queues.Queues) != 1 || queues.Queues[0].Name != c.flags[3] {
+		t.Error("Expected the queue to be listed, got", queues.Queues)
+	}
+	c.decode(&purged, "purge", "-yes", "-unregister")
+	c.decode(&queues, "queues")
+	if len(queues.Queues) != 0 {
+		t.Error("Expected the queue to be unregistered, got", queues.Queues)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	c, teardown := setup(t)
+	defer teardown()
+	dead := &cli{t: t, flags: append(append([]string{}, c.flags...), "-queue", c.flags[3]+"-dead")}
+
+	var pushed struct{ IDs []string }
+	dead.decode(&pushed, "push", "failed", "again")
+	c.decode(&pushed, "requeue", "-from", c.flags[3]+"-dead")
+	if len(pushed.IDs) != 2 {
+		t.Error("Expected 2 jobs requeued, got", pushed.IDs)
+	}
+	var stats airq.Stats
+	dead.decode(&stats, "stats")
+	if stats.Pending != 0 || stats.Reserved != 0 {
+		t.Error("Expected the dead-letter queue to be empty, got", stats)
+	}
+}
+
+func TestEncryptedPop(t *testing.T) {
+	c, teardown := setup(t)
+	defer teardown()
+	keys := "k1=" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	encrypted := &cli{t: t, flags: append(append([]string{}, c.flags...), "-keys", keys, "-offload", "0")}
+
+	var pushed struct{ IDs []string }
+	encrypted.decode(&pushed, "push", "secret")
+
+	// without the key, the job is pushed back, to be popped later
+	if code, _, errOut := c.run("", "pop"); code != 1 || errOut == "" {
+		t.Error("Expected pop to fail without the key, got", code, errOut)
+	}
+	c.decode(&pushed, "reschedule", pushed.IDs[0])
+	var popped jobsOutput
+	encrypted.decode(&popped, "pop")
+	if len(popped.Jobs) != 1 || popped.Jobs[0].Content != "secret" {
+		t.Error("Expected the encrypted job, got", popped.Jobs)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	c, teardown := setup(t)
+	defer teardown()
+
+	for name, args := range map[string][]string{
+		"unknown command": {"unknown"},
+		"unknown flag":    {"-unknown", "stats"},
+		"no queue":        {"-queue", "", "stats"},
+		"purge":           {"purge"},
+		"push":            {"push"},
+		"when and in":     {"push", "-when", "2030-01-01T00:00:00Z", "-in", "1h", "content"},
+		"get":             {"get"},
+		"requeue":         {"requeue"},
+		"invalid key":     {"-keys", "k1", "stats"},
+		"short key":       {"-keys", "k1=AAAA", "stats"},
+	} {
+		if code, _, _ := c.run("", args...); code != 2 {
+			t.Error(name, "expected exit code 2, got", code)
+		}
+	}
+	if code, _, _ := c.run("", "-h"); code != 0 {
+		t.Error("Expected help to exit with 0, got", code)
+	}
+	if code, _, _ := c.run("", "get", "missing"); code != 1 {
+		t.Error("Expected a missing job to exit with 1, got", code)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	// a server never replying
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	c := &cli{t: t, flags: []string{"-redis", "redis://" + l.Addr().String(), "-queue", "q", "-timeout", "100ms"}}
+	done := make(chan int)
+	go func() {
+		code, _, _ := c.run("", "stats")
+		done <- code
+	}()
+	select {
+	case code := <-done:
+		if code != 1 {
+			t.Error("Expected the command to fail, got", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the command to time out")
+	}
+}
+
+func TestStorageOptions(t *testing.T) {
+	t.Parallel()
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	for _, tc := range []struct {
+		keys, blobDir string
+		offload, opts int
+		err           bool
+	}{
+		{opts: 0},
+		{keys: "k2=" + key + ",k1=" + key, opts: 1},
+		{keys: "k1=" + key, offload: 10, opts: 2},
+		{blobDir: t.TempDir(), opts: 1},
+		{keys: "k1=not base64", err: true},
+		{keys: "=" + key, err: true},
+	} {
+		opts, err := storageOptions(tc.keys, tc.offload, tc.blobDir)
+		if (err != nil) != tc.err || len(opts) != tc.opts {
+			t.Error("Unexpected options for", tc, len(opts), err)
+		}
+	}
+}
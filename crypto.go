@@ -0,0 +1,109 @@
+package airq
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// KeyRing holds the AES keys used to encrypt job contents at rest.
+// Jobs are encrypted with the current key and keep its id, so older keys
+// can stay in the ring to decrypt pending jobs after a rotation.
+type KeyRing struct {
+	current string
+	idKey   []byte // HMAC key of the job ids, derived from the current key
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyRing defines a KeyRing from AES-128, AES-192 or AES-256 keys indexed
+// by their id, current being the id of the key used to encrypt new jobs.
+func NewKeyRing(current string, keys map[string][]byte) (*KeyRing, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current key %q not found in key ring", current)
+	}
+	mac := hmac.New(sha256.New, keys[current])
+	mac.Write([]byte("airq job id"))
+	r := &KeyRing{current: current, idKey: mac.Sum(nil), keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" {
+			return nil, fmt.Errorf("key id can't be empty")
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		if r.keys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+	}
+	return r, nil
+}
+
+// WithEncryption encrypts job contents with AES-GCM before storing them. The
+// ids of UpdateStrategy jobs are then an HMAC of their contents keyed from the
+// current key, jobs pushed before and after a rotation having different ids.
+func WithEncryption(keys *KeyRing) Option { return func(q *Queue) { q.keys = keys } }
+
+// jobID returns the id of an UpdateStrategy job, an HMAC of its contents.
+func (r *KeyRing) jobID(j *Job) string {
+	mac := hmac.New(sha256.New, r.idKey)
+	j.writeContents(mac)
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// seal encrypts the compressed contents of j, bound to its id.
+func (r *KeyRing) seal(j *Job) error {
+	aead := r.keys[r.current]
+	content, err := encrypt(aead, []byte(j.CompressedContent), j.ID)
+	if err != nil {
+		return err
+	}
+	j.CompressedContent = string(content)
+	if len(j.CompressedPayload) > 0 {
+		if j.CompressedPayload, err = encrypt(aead, j.CompressedPayload, j.ID); err != nil {
+			return err
+		}
+	}
+	j.Key = r.current
+	return nil
+}
+
+// open decrypts the compressed contents of j.
+func (r *KeyRing) open(j *Job) error {
+	aead, ok := r.keys[j.Key]
+	if !ok {
+		return fmt.Errorf("job %s is encrypted with unknown key %q", j.ID, j.Key)
+	}
+	content, err := decrypt(aead, []byte(j.CompressedContent), j.ID)
+	if err != nil {
+		return fmt.Errorf("can't decrypt job %s: %w", j.ID, err)
+	}
+	j.CompressedContent = string(content)
+	if len(j.CompressedPayload) > 0 {
+		if j.CompressedPayload, err = decrypt(aead, j.CompressedPayload, j.ID); err != nil {
+			return fmt.Errorf("can't decrypt job %s: %w", j.ID, err)
+		}
+	}
+	return nil
+}
+
+func encrypt(aead cipher.AEAD, in []byte, id string) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(in)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, in, []byte(id)), nil
+}
+
+func decrypt(aead cipher.AEAD, in []byte, id string) ([]byte, error) {
+	if len(in) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, in := in[:aead.NonceSize()], in[aead.NonceSize():]
+	return aead.Open(nil, nonce, in, []byte(id))
+}
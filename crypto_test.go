@@ -0,0 +1,155 @@
+package airq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/shamaton/msgpackgen/msgpack"
+)
+
+func TestEncryption(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	key1, key2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16)
+	WithEncryption(mustKeyRing(t, "k1", map[string][]byte{"k1": key1}))(q)
+
+	ids, err := q.Push(&Job{Content: "secret content", Payload: []byte("secret payload")})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	c, _ := q.Conn()
+	raw, err := redis.Bytes(c.Do("HGET", q.Name+":values", ids[0]))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	j, err := newJobFromBytes(raw, nil)
+	if err == nil || j != nil {
+		t.Error("Expected encrypted job to require a key ring")
+	}
+	var stored Job
+	if err := msgpack.Unmarshal(raw, &stored); err != nil {
+		t.Error(err)
+	}
+	if stored.Key != "k1" || strings.Contains(string(uncompress([]byte(stored.CompressedContent))), "secret") {
+		t.Error("Expected job contents to be encrypted with k1")
+	}
+
+	// consumers without the key push the job back
+	WithEncryption(nil)(q)
+	if jobs, err := q.PopJobs(1); err == nil || len(jobs) != 0 {
+		t.Error("Expected an error for an encrypted job, got", jobs, err)
+	}
+	if pending, _ := q.Pending(); pending != 1 {
+		t.Error("Expected the encrypted job to be pushed back, got", pending)
+	}
+	if err := q.Reschedule(time.Now(), ids[0]); err != nil {
+		t.Error(err)
+	}
+
+	// rotate keys, jobs encrypted with k1 can still be read
+	WithEncryption(mustKeyRing(t, "k2", map[string][]byte{"k1": key1, "k2": key2}))(q)
+	if _, err := q.Push(&Job{Content: "other secret"}); err != nil {
+		t.Error(err)
+	}
+	jobs, err := q.PopJobs(2)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[0].Content != "secret content" || string(jobs[0].Payload) != "secret payload" || jobs[1].Content != "other secret" {
+		t.Error("Expected to decrypt jobs, got", jobs)
+	}
+}
+
+func TestUndecodableJobs(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	now := time.Now()
+	WithClock(func() time.Time { return now })(q)
+
+	WithEncryption(mustKeyRing(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}))(q)
+	addJobs(t, q, Job{Content: "secret", When: now.Add(-time.Hour)})
+	WithEncryption(nil)(q)
+	addJobs(t, q, Job{Content: "plain", When: now.Add(-time.Minute)})
+
+	if jobs, err := q.PopJobs(1); err == nil || len(jobs) != 0 {
+		t.Error("Expected an error for the encrypted job, got", jobs, err)
+	}
+	// the undecodable job doesn't block the queue
+	if j, err := q.Pop(); err != nil || j == nil || j.Content != "plain" {
+		t.Error("Expected the plain job popped, got", j, err)
+	}
+	if j, err := q.Pop(); err != nil || j != nil {
+		t.Error("Expected the encrypted job to be delayed, got", j, err)
+	}
+	if stats, _ := q.Stats(); stats != (Stats{Pending: 1}) {
+		t.Error("Expected the encrypted job pending, got", stats)
+	}
+	now = now.Add(DefaultUndecodableDelay)
+	if jobs, err := q.PopJobs(1); err == nil || len(jobs) != 0 {
+		t.Error("Expected the encrypted job popped again after the delay, got", jobs, err)
+	}
+}
+
+func TestEncryptedIDs(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	WithEncryption(mustKeyRing(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}))(q)
+
+	ids, err := q.Push(&Job{Content: "jane@example.com"}, &Job{Content: "jane@example.com"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if ids[0] != ids[1] {
+		t.Error("Expected jobs with the same contents to have the same id, got", ids)
+	}
+	c, _ := q.Conn()
+	members, _ := redis.Strings(c.Do("ZRANGE", q.key(), 0, -1))
+	plain := (&Job{Content: "jane@example.com"}).generateID()
+	if len(members) != 1 || members[0] != ids[0] || members[0] == plain {
+		t.Error("Expected the stored id not to be the hash of the contents, got", members)
+	}
+}
+
+func TestKeyRing(t *testing.T) {
+	t.Parallel()
+	if _, err := NewKeyRing("missing", map[string][]byte{"k1": make([]byte, 32)}); err == nil {
+		t.Error("Expected an error for a missing current key")
+	}
+	if _, err := NewKeyRing("k1", map[string][]byte{"k1": make([]byte, 10)}); err == nil {
+		t.Error("Expected an error for an invalid key size")
+	}
+
+	keys := mustKeyRing(t, "k1", map[string][]byte{"k1": make([]byte, 32)})
+	j := &Job{ID: "01", Content: "content"}
+	b, err := j.encode(keys)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	other := mustKeyRing(t, "k2", map[string][]byte{"k2": make([]byte, 32)})
+	if _, err := newJobFromBytes(b, other); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+	out, err := newJobFromBytes(b, keys)
+	if err != nil || out.Content != "content" {
+		t.Error("Expected to decrypt job", out, err)
+	}
+}
+
+func mustKeyRing(t *testing.T, current string, keys map[string][]byte) *KeyRing {
+	t.Helper()
+	r, err := NewKeyRing(current, keys)
+	if err != nil {
+		t.Error(err)
+	}
+	return r
+}
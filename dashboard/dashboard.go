@@ -0,0 +1,278 @@
+// Package dashboard serves a web page to inspect queues: their counters, their
+// jobs page by page, and buttons to remove, reschedule or requeue jobs from
+// dead-letter queues. It doesn't authenticate users, mount it behind your own
+// middleware.
+//
+//	GET    /                                    the web page
+//	GET    /api/queues                          queues and their counters
+//	POST   /api/queues/{name}/requeue           move jobs of a dead-letter queue back: {"ids": ["1"]}
+//	GET    /api/queues/{name}/jobs, /jobs/{id}  list and get jobs, as the gateway API of the queue
+//	DELETE /api/queues/{name}/jobs?id=1         remove jobs
+//	POST   /api/queues/{name}/jobs/reschedule   reschedule jobs
+//	GET    /api/queues/{name}/stats             queue counters
+//
+// Jobs can't be pushed, popped or acknowledged through the dashboard.
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/gateway"
+)
+
+//go:embed static
+var static embed.FS
+
+// Option configures the dashboard.
+type Option func(*handler)
+
+// WithQueueOptions sets the options of the queues, e.g. their encryption keys.
+func WithQueueOptions(opts ...airq.Option) Option {
+	return func(h *handler) { h.queueOpts = append(h.queueOpts, opts...) }
+}
+
+// WithDeadLetters sets the dead-letter queue of queues, by queue name. Jobs
+// of a dead-letter queue can be requeued to their queue.
+func WithDeadLetters(deadLetters map[string]string) Option {
+	return func(h *handler) {
+		for name, dead := range deadLetters {
+			h.deadLetters[name] = dead
+			h.requeueTo[dead] = name
+		}
+	}
+}
+
+// Queue is the JSON representation of a queue.
+type Queue struct {
+	Name  string     `json:"name"`
+	Stats airq.Stats `json:"stats"`
+	// DeadLetter is the name of the dead-letter queue of the queue.
+	DeadLetter string `json:"dead_letter,omitempty"`
+	// RequeueTo is the name of the queue jobs are requeued to, for dead-letter queues.
+	RequeueTo string `json:"requeue_to,omitempty"`
+}
+
+type handler struct {
+	deadLetters map[string]string
+	discover    bool
+	names       []string
+	pool        *redis.Pool
+	queueOpts   []airq.Option
+	requeueTo   map[string]string
+
+	sync.Mutex
+	queues map[string]*queue
+}
+
+// queue is a queue and its gateway.
+type queue struct {
+	*airq.Queue
+	api http.Handler
+}
+
+// New returns a handler showing the named queues of pool and their dead-letter
+// queues, all the registered queues (see airq.ListQueues) if queues is empty.
+// It's to mount on a mux with http.StripPrefix. pool may be nil if the queue
+// options give a connection provider, e.g. airq.WithCluster.
+func New(pool *redis.Pool, queues []string, opts ...Option) http.Handler {
+	h := &handler{
+		deadLetters: make(map[string]string),
+		discover:    len(queues) == 0,
+		pool:        pool,
+		queues:      make(map[string]*queue),
+		requeueTo:   make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	for _, name := range queues {
+		if !contains(h.names, name) {
+			h.names = append(h.names, name)
+		}
+	}
+	for dead := range h.requeueTo {
+		if !contains(h.names, dead) {
+			h.names = append(h.names, dead)
+		}
+	}
+	sort.Strings(h.names)
+
+	assets, _ := fs.Sub(static, "static")
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	mux.HandleFunc("/api/queues", h.list)
+	mux.HandleFunc("/api/queues/", h.serveQueue)
+	return mux
+}
+
+// queueNames returns the names of the queues shown.
+func (h *handler) queueNames() ([]string, error) {
+	if !h.discover {
+		return h.names, nil
+	}
+	var c redis.Conn
+	if h.pool != nil {
+		c = h.pool.Get()
+		defer c.Close()
+	}
+	names, err := airq.ListQueueNames(c, h.queueOpts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range h.names {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// get returns the named queue, nil if not shown.
+func (h *handler) get(name string) (*queue, error) {
+	names, err := h.queueNames()
+	if err != nil || !contains(names, name) {
+		return nil, err
+	}
+	return h.queue(name), nil
+}
+
+// queue returns the named queue, created on first use.
+func (h *handler) queue(name string) *queue {
+	h.Lock()
+	defer h.Unlock()
+	q, ok := h.queues[name]
+	if !ok {
+		aq := airq.New(name, append([]airq.Option{airq.WithPool(h.pool)}, h.queueOpts...)...)
+		q = &queue{Queue: aq, api: gateway.New(aq)}
+		h.queues[name] = q
+	}
+	return q
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	names, err := h.queueNames()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	res := make([]Queue, 0, len(names))
+	for _, name := range names {
+		stats, err := h.queue(name).Stats()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		res = append(res, Queue{
+			Name:       name,
+			Stats:      stats,
+			DeadLetter: h.deadLetters[name],
+			RequeueTo:  h.requeueTo[name],
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string][]Queue{"queues": res})
+}
+
+// serveQueue serves the API of a queue.
+func (h *handler) serveQueue(w http.ResponseWriter, r *http.Request) {
+	name, path := strings.TrimPrefix(r.URL.Path, "/api/queues/"), "/"
+	if i := strings.Index(name, "/"); i >= 0 {
+		name, path = name[:i], name[i:]
+	}
+	q, err := h.get(name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if q == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown queue " + name})
+		return
+	}
+	if path == "/requeue" {
+		h.requeue(w, r, q)
+		return
+	}
+	if !exposed(r.Method, path) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = path
+	q.api.ServeHTTP(w, r2)
+}
+
+// exposed reports whether the gateway route of path is used by the page, other
+// routes (push, pop, ack, ...) not being served.
+func exposed(method, path string) bool {
+	switch {
+	case path == "/jobs":
+		return method == http.MethodGet || method == http.MethodDelete
+	case path == "/jobs/reschedule":
+		return method == http.MethodPost
+	case path == "/jobs/ack", path == "/jobs/nack":
+		return false
+	case strings.HasPrefix(path, "/jobs/"), path == "/stats":
+		return method == http.MethodGet
+	}
+	return false
+}
+
+// requeue moves jobs of a dead-letter queue to its queue.
+func (h *handler) requeue(w http.ResponseWriter, r *http.Request, q *queue) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	to, ok := h.requeueTo[q.Name]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": q.Name + " is not a dead-letter queue"})
+		return
+	}
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := q.Requeue(h.queue(to).Queue, req.IDs...); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, airq.ErrNoIDs):
+			code = http.StatusBadRequest
+		case errors.Is(err, airq.ErrNotFound):
+			code = http.StatusNotFound
+		}
+		writeJSON(w, code, map[string]string{"error": err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,110 @@
+"use strict";
+
+const pageSize = 50;
+let current = null;
+let offset = 0;
+
+function $(id) { return document.getElementById(id); }
+
+async function api(method, path, body) {
+  const res = await fetch("api/queues" + path, {
+    method: method,
+    headers: body ? { "Content-Type": "application/json" } : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  if (res.status === 204) return null;
+  const data = await res.json();
+  if (!res.ok) throw new Error(data.error || res.statusText);
+  return data;
+}
+
+function cell(row, text, className) {
+  const td = row.insertCell();
+  td.textContent = text;
+  if (className) td.className = className;
+  return td;
+}
+
+async function loadQueues() {
+  const { queues } = await api("GET", "");
+  const body = $("queues").tBodies[0];
+  body.replaceChildren();
+  for (const q of queues) {
+    const row = body.insertRow();
+    if (current && current.name === q.name) {
+      row.className = "selected";
+      current = q;
+    }
+    cell(row, q.name);
+    cell(row, q.stats.pending);
+    cell(row, q.stats.due);
+    cell(row, q.stats.reserved);
+    cell(row, q.dead_letter || (q.requeue_to ? "of " + q.requeue_to : ""));
+    row.onclick = () => select(q);
+  }
+}
+
+function select(q) {
+  current = q;
+  offset = 0;
+  $("queue").hidden = false;
+  $("queue-name").textContent = q.name;
+  $("requeue").hidden = !q.requeue_to;
+  loadQueues();
+  loadJobs();
+}
+
+async function loadJobs() {
+  const { jobs } = await api("GET", "/" + encodeURIComponent(current.name) + "/jobs?limit=" + pageSize + "&offset=" + offset);
+  const body = $("jobs").tBodies[0];
+  body.replaceChildren();
+  $("all").checked = false;
+  for (const j of jobs) {
+    const row = body.insertRow();
+    const box = document.createElement("input");
+    box.type = "checkbox";
+    box.value = j.id;
+    row.insertCell().appendChild(box);
+    cell(row, j.id);
+    cell(row, new Date(j.when).toLocaleString());
+    cell(row, j.subject || "");
+    cell(row, j.headers ? Object.entries(j.headers).map(([k, v]) => k + "=" + v).join(", ") : "");
+    cell(row, j.content || (j.payload ? "(" + atob(j.payload).length + " bytes)" : ""), "content");
+  }
+  $("page").textContent = jobs.length ? (offset + 1) + "–" + (offset + jobs.length) + " of " + current.stats.pending : "no jobs";
+  $("prev").disabled = offset === 0;
+  $("next").disabled = jobs.length < pageSize;
+}
+
+function selected() {
+  return Array.from($("jobs").querySelectorAll("tbody input:checked"), box => box.value);
+}
+
+async function action(fn) {
+  $("error").textContent = "";
+  const ids = selected();
+  if (!ids.length) return;
+  try {
+    await fn(ids, "/" + encodeURIComponent(current.name));
+  } catch (e) {
+    $("error").textContent = e.message;
+  }
+  await loadQueues();
+  await loadJobs();
+}
+
+$("remove").onclick = () => action((ids, path) =>
+  api("DELETE", path + "/jobs?" + ids.map(id => "id=" + encodeURIComponent(id)).join("&")));
+$("reschedule").onclick = () => action((ids, path) => {
+  const when = $("when").value ? new Date($("when").value) : new Date();
+  return api("POST", path + "/jobs/reschedule", { ids: ids, when: when.toISOString() });
+});
+$("requeue").onclick = () => action((ids, path) => api("POST", path + "/requeue", { ids: ids }));
+$("all").onchange = e => {
+  for (const box of $("jobs").querySelectorAll("tbody input")) box.checked = e.target.checked;
+};
+$("prev").onclick = () => { offset = Math.max(0, offset - pageSize); loadJobs(); };
+$("next").onclick = () => { offset += pageSize; loadJobs(); };
+
+loadQueues();
+setInterval(loadQueues, 5000);
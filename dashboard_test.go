@@ -0,0 +1,121 @@
+package airq_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/dashboard"
+	"github.com/jney/airq/gateway"
+)
+
+func TestDashboard(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	dead := airq.New(q.Name+"-dead", airq.WithPool(q.Pool))
+	defer func() {
+		conn := q.Pool.Get()
+		conn.Do("DEL", dead.Name, dead.Name+":values", dead.Name+":reserved")
+		conn.Do("SREM", "airq:queues", dead.Name)
+		conn.Close()
+	}()
+
+	if _, err := q.Push(&airq.Job{Content: "foo"}, &airq.Job{Content: "bar"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := dead.Push(&airq.Job{ID: "01", Content: "failed"}); err != nil {
+		t.Error(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", dashboard.New(q.Pool, []string{q.Name},
+		dashboard.WithDeadLetters(map[string]string{q.Name: dead.Name}),
+	)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	do := func(method, path, body string, code int, out interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+"/dashboard"+path, strings.NewReader(body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != code {
+			t.Errorf("%s %s: expected status %d, got %d", method, path, code, res.StatusCode)
+		}
+		if out != nil {
+			if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	do("GET", "/", "", http.StatusOK, nil)
+	do("GET", "/app.js", "", http.StatusOK, nil)
+
+	var list struct{ Queues []dashboard.Queue }
+	do("GET", "/api/queues", "", http.StatusOK, &list)
+	if len(list.Queues) != 2 || list.Queues[0].Name != q.Name || list.Queues[0].Stats.Pending != 2 ||
+		list.Queues[0].DeadLetter != dead.Name || list.Queues[1].RequeueTo != q.Name {
+		t.Error("Expected the queue and its dead-letter queue, got", list.Queues)
+	}
+
+	var page struct{ Jobs []gateway.Job }
+	do("GET", "/api/queues/"+q.Name+"/jobs?limit=1&offset=1", "", http.StatusOK, &page)
+	if len(page.Jobs) != 1 || page.Jobs[0].Content != "bar" {
+		t.Error("Expected the second job, got", page.Jobs)
+	}
+	do("GET", "/api/queues/unknown/jobs", "", http.StatusNotFound, nil)
+	do("GET", "/api/queues/"+q.Name+"/stats", "", http.StatusOK, nil)
+	// jobs can't be pushed nor popped
+	do("POST", "/api/queues/"+q.Name+"/jobs", `{"jobs": [{"content": "foo"}]}`, http.StatusNotFound, nil)
+	do("POST", "/api/queues/"+q.Name+"/pop", "", http.StatusNotFound, nil)
+	do("POST", "/api/queues/"+q.Name+"/jobs/ack", `{"ids": ["01"]}`, http.StatusNotFound, nil)
+	if n, _ := q.Pending(); n != 2 {
+		t.Error("Expected the jobs to be kept, got", n)
+	}
+
+	do("POST", "/api/queues/"+q.Name+"/requeue", `{"ids": ["01"]}`, http.StatusBadRequest, nil)
+	do("POST", "/api/queues/"+dead.Name+"/requeue", `{"ids": ["01"]}`, http.StatusNoContent, nil)
+	if j, _ := q.Get("01"); j == nil || j.Content != "failed" {
+		t.Error("Expected job to be requeued, got", j)
+	}
+	if n, _ := dead.Pending(); n != 0 {
+		t.Error("Expected no job left in the dead-letter queue, got", n)
+	}
+}
+
+func TestDashboardDiscovery(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	ns := randomName()
+	defer func() {
+		conn := q.Pool.Get()
+		conn.Do("DEL", "airq:queues:"+ns)
+		conn.Close()
+	}()
+	if err := airq.New(q.Name, airq.WithPool(q.Pool), airq.WithNamespace(ns)).Register(); err != nil {
+		t.Error(err)
+	}
+
+	srv := httptest.NewServer(dashboard.New(q.Pool, nil, dashboard.WithQueueOptions(airq.WithNamespace(ns))))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/api/queues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var list struct{ Queues []dashboard.Queue }
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		t.Error(err)
+	}
+	if len(list.Queues) != 1 || list.Queues[0].Name != q.Name {
+		t.Error("Expected the registered queue, got", list.Queues)
+	}
+}
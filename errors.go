@@ -0,0 +1,22 @@
+package airq
+
+import "errors"
+
+var (
+	// ErrLimitZero is returned when popping or peeking without a positive limit.
+	ErrLimitZero = errors.New("limit must be greater than 0")
+	// ErrNoConnection is returned by queues without connection, pool, provider or executor.
+	ErrNoConnection = errors.New("no connection defined")
+	// ErrNoIDs is returned when an operation on jobs is given no id.
+	ErrNoIDs = errors.New("no id provided")
+	// ErrNoJobs is returned by Push without job.
+	ErrNoJobs = errors.New("no jobs provided")
+	// ErrNotFound is returned when jobs are not in the queue.
+	ErrNotFound = errors.New("job not found")
+	// ErrNotReserved is returned when acknowledging or releasing jobs that are not reserved.
+	ErrNotReserved = errors.New("job not reserved")
+	// ErrPayloadTooLarge is returned by Push when a job exceeds the maximum payload size.
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrUnavailable is returned by clients when the server or its redis server can't be reached.
+	ErrUnavailable = errors.New("queue unavailable")
+)
@@ -0,0 +1,83 @@
+package airq
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Executor runs the commands and scripts of a queue, e.g. a redigo connection
+// (see Redigo) or a go-redis client (see the goredis package). Replies are
+// those of redigo: bulk strings as []byte, nil replies as redis.ErrNil and
+// error replies as redis.Error.
+type Executor interface {
+	Do(cmd string, args ...interface{}) (interface{}, error)
+	// EvalSha runs the script of SHA1 sha, failing with a NOSCRIPT error if
+	// it's not loaded.
+	EvalSha(sha string, keys []string, args ...interface{}) (interface{}, error)
+	// Eval runs script, loading it.
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// WithExecutor runs the commands of the queue with e, taking precedence over
+// WithConnProvider, WithPool and WithConn.
+func WithExecutor(e Executor) Option { return func(q *Queue) { q.executor = e } }
+
+// Redigo returns an Executor running commands on c.
+func Redigo(c redis.Conn) Executor { return redigoExecutor{c} }
+
+type redigoExecutor struct{ redis.Conn }
+
+func (e redigoExecutor) EvalSha(sha string, keys []string, args ...interface{}) (interface{}, error) {
+	return e.Do("EVALSHA", redis.Args{sha, len(keys)}.AddFlat(keys).Add(args...)...)
+}
+
+func (e redigoExecutor) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	return e.Do("EVAL", redis.Args{script, len(keys)}.AddFlat(keys).Add(args...)...)
+}
+
+// exec returns the executor of the queue and the func releasing it.
+func (q *Queue) exec() (Executor, func()) { return q.executorFor(q.key()) }
+
+// executorFor returns the executor of the commands on key, on a cluster the
+// node serving it, and the func releasing it.
+func (q *Queue) executorFor(key string) (Executor, func()) {
+	if q.executor != nil {
+		return q.executor, func() {}
+	}
+	c, managed := q.connFor(key)
+	if !managed {
+		return Redigo(c), func() {}
+	}
+	return Redigo(c), func() { c.Close() }
+}
+
+// script is a Lua script run with EVALSHA, and EVAL if not loaded yet.
+type script struct {
+	keyCount int
+	src      string
+	sha      string
+}
+
+func newScript(keyCount int, src string) *script {
+	h := sha1.Sum([]byte(src))
+	return &script{keyCount: keyCount, src: src, sha: hex.EncodeToString(h[:])}
+}
+
+// Do runs the script, its keys being the first keysAndArgs.
+func (s *script) Do(e Executor, keysAndArgs ...interface{}) (interface{}, error) {
+	keys := make([]string, s.keyCount)
+	for i := range keys {
+		keys[i], _ = keysAndArgs[i].(string)
+	}
+	args := keysAndArgs[s.keyCount:]
+	reply, err := e.EvalSha(s.sha, keys, args...)
+	var rErr redis.Error
+	if errors.As(err, &rErr) && strings.HasPrefix(string(rErr), "NOSCRIPT ") {
+		reply, err = e.Eval(s.src, keys, args...)
+	}
+	return reply, err
+}
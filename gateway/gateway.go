@@ -0,0 +1,356 @@
+// Package gateway exposes a queue as JSON over HTTP, mirroring the gRPC Jobs service.
+//
+//	POST   /jobs              push jobs: {"jobs": [{"content": "...", "when": "..."}]}
+//	GET    /jobs?limit=10     list the next jobs, from offset if set
+//	GET    /jobs/{id}         get a job
+//	DELETE /jobs?id=1&id=2    remove jobs
+//	POST   /jobs/ack          acknowledge reserved jobs: {"ids": ["1"]}
+//	POST   /jobs/nack         release reserved jobs: {"ids": ["1"], "delay": "30s"}
+//	POST   /jobs/reschedule   reschedule jobs: {"ids": ["1"], "when": "..."}, when being required
+//	POST   /pop?limit=10      pop jobs, reserved if a visibility timeout (e.g. "1m") is given
+//	GET    /stats             queue counters
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/internal/rpcerr"
+	"github.com/jney/airq/internal/validate"
+	"google.golang.org/grpc/codes"
+)
+
+// Job is the JSON representation of a job.
+type Job struct {
+	Content  string            `json:"content,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	ID       string            `json:"id,omitempty"`
+	Payload  []byte            `json:"payload,omitempty"` // base64 encoded
+	Strategy airq.Strategy     `json:"strategy,omitempty"`
+	Subject  string            `json:"subject,omitempty"`
+	When     *time.Time        `json:"when,omitempty"` // now if not set
+}
+
+// DefaultMaxBodySize is the maximum size of request bodies, unless set with
+// WithMaxBodySize.
+const DefaultMaxBodySize = 10 << 20
+
+type handler struct {
+	q           *airq.Queue
+	limits      validate.Limits
+	maxBodySize int64
+}
+
+// Option configures a gateway.
+type Option func(*handler)
+
+// WithMaxBodySize bounds the size of request bodies, DefaultMaxBodySize by default.
+func WithMaxBodySize(n int64) Option { return func(h *handler) { h.maxBodySize = n } }
+
+// WithMaxBatchSize bounds the count of jobs pushed at once, 1000 by default,
+// unlimited if 0.
+func WithMaxBatchSize(n int) Option { return func(h *handler) { h.limits.MaxBatchSize = n } }
+
+// WithMaxJobSize bounds the size of the content and payload of pushed jobs.
+func WithMaxJobSize(n int) Option { return func(h *handler) { h.limits.MaxJobSize = n } }
+
+// New returns a handler serving q, to mount on a mux with http.StripPrefix.
+// Pushed jobs are checked as by the gRPC server.
+func New(q *airq.Queue, opts ...Option) http.Handler {
+	h := handler{
+		q:           q,
+		limits:      validate.Limits{MaxBatchSize: validate.DefaultMaxBatchSize},
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", h.jobs)
+	mux.HandleFunc("/jobs/", h.job)
+	mux.HandleFunc("/pop", h.pop)
+	mux.HandleFunc("/stats", h.stats)
+	return mux
+}
+
+// errBadRequest marks errors caused by an invalid request.
+var errBadRequest = errors.New("bad request")
+
+func (h handler) jobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Jobs []Job `json:"jobs"`
+		}
+		if err := h.decode(w, r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+		if len(req.Jobs) == 0 {
+			writeError(w, fmt.Errorf("%w: no jobs provided", errBadRequest))
+			return
+		}
+		jobs := make([]*airq.Job, len(req.Jobs))
+		for i, j := range req.Jobs {
+			jobs[i] = j.job()
+		}
+		if err := h.limits.Jobs(jobs); err != nil {
+			writeError(w, err)
+			return
+		}
+		ids, err := h.q.Push(jobs...)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string][]string{"ids": ids})
+	case http.MethodGet:
+		limit, err := intParam(r, "limit", 10)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		offset, err := intParam(r, "offset", 0)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		jobs, err := h.q.List(offset, limit)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJobs(w, jobs)
+	case http.MethodDelete:
+		ids := r.URL.Query()["id"]
+		if len(ids) == 0 {
+			writeError(w, fmt.Errorf("%w: no id provided", errBadRequest))
+			return
+		}
+		if err := h.q.Remove(ids...); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost, http.MethodDelete)
+	}
+}
+
+func (h handler) job(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	switch id {
+	case "ack", "nack", "reschedule":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		h.update(w, r, id)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	j, err := h.q.Get(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if j == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("job %s not found", id)})
+		return
+	}
+	writeJSON(w, http.StatusOK, FromJob(j))
+}
+
+// update acknowledges, releases or reschedules jobs.
+func (h handler) update(w http.ResponseWriter, r *http.Request, op string) {
+	var req struct {
+		Delay duration  `json:"delay"`
+		IDs   []string  `json:"ids"`
+		When  time.Time `json:"when"`
+	}
+	if err := h.decode(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(req.IDs) == 0 {
+		writeError(w, fmt.Errorf("%w: no id provided", errBadRequest))
+		return
+	}
+	if op == "reschedule" && req.When.IsZero() {
+		writeError(w, fmt.Errorf("%w: no execution time provided", errBadRequest))
+		return
+	}
+	var err error
+	switch op {
+	case "ack":
+		err = h.q.Ack(req.IDs...)
+	case "nack":
+		err = h.q.Nack(time.Duration(req.Delay), req.IDs...)
+	case "reschedule":
+		err = h.q.Reschedule(req.When, req.IDs...)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h handler) pop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	limit, err := intParam(r, "limit", 1)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var jobs []*airq.Job
+	if v := r.URL.Query().Get("visibility"); v != "" {
+		var visibility time.Duration
+		if visibility, err = time.ParseDuration(v); err != nil {
+			writeError(w, fmt.Errorf("%w: visibility: %v", errBadRequest, err))
+			return
+		}
+		jobs, err = h.q.Reserve(limit, visibility)
+	} else {
+		jobs, err = h.q.PopJobs(limit)
+	}
+	// popped jobs are lost if not returned, the error is only reported if none could be decoded
+	if err != nil && len(jobs) == 0 {
+		writeError(w, err)
+		return
+	}
+	writeJobs(w, jobs)
+}
+
+func (h handler) stats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	stats, err := h.q.Stats()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (j Job) job() *airq.Job {
+	job := &airq.Job{
+		Content:  j.Content,
+		Headers:  j.Headers,
+		ID:       j.ID,
+		Payload:  j.Payload,
+		Strategy: j.Strategy,
+		Subject:  j.Subject,
+	}
+	if j.When != nil {
+		job.When = *j.When
+	}
+	return job
+}
+
+// FromJob returns the JSON representation of j.
+func FromJob(j *airq.Job) Job {
+	when := j.When
+	return Job{
+		Content:  j.Content,
+		Headers:  j.Headers,
+		ID:       j.ID,
+		Payload:  j.Payload,
+		Strategy: j.Strategy,
+		Subject:  j.Subject,
+		When:     &when,
+	}
+}
+
+// duration is a time.Duration decoded from a JSON string such as "30s".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	*d = duration(v)
+	return err
+}
+
+// decode reads the JSON body of r, up to the maximum body size.
+func (h handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	body := r.Body
+	if h.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, v)
+	}
+	return i, nil
+}
+
+func writeJobs(w http.ResponseWriter, jobs []*airq.Job) {
+	res := make([]Job, len(jobs))
+	for i, j := range jobs {
+		res[i] = FromJob(j)
+	}
+	writeJSON(w, http.StatusOK, map[string][]Job{"jobs": res})
+}
+
+// writeError writes err with the HTTP status of its gRPC code, as returned
+// by the server.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, errBadRequest) || errors.Is(err, validate.ErrInvalid) {
+		code = http.StatusBadRequest
+	} else {
+		switch rpcerr.Code(err) {
+		case codes.InvalidArgument:
+			code = http.StatusBadRequest
+		case codes.NotFound:
+			code = http.StatusNotFound
+		case codes.FailedPrecondition:
+			code = http.StatusConflict
+		case codes.Unavailable:
+			code = http.StatusServiceUnavailable
+		case codes.DeadlineExceeded:
+			code = http.StatusGatewayTimeout
+		}
+	}
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,110 @@
+package airq_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/gateway"
+)
+
+func TestGateway(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	mux := http.NewServeMux()
+	mux.Handle("/queue/", http.StripPrefix("/queue", gateway.New(q, gateway.WithMaxBodySize(1<<10))))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	do := func(method, path, body string, code int, out interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+"/queue"+path, strings.NewReader(body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != code {
+			t.Errorf("%s %s: expected status %d, got %d", method, path, code, res.StatusCode)
+		}
+		if out != nil {
+			if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	var pushed struct{ IDs []string }
+	do("POST", "/jobs", `{"jobs": [
+		{"id": "01", "content": "foo", "subject": "greeting", "when": "2000-01-01T00:00:00Z"},
+		{"id": "02", "payload": "AAEC", "when": "2000-01-02T00:00:00Z"},
+		{"id": "03", "content": "baz", "when": "2100-01-01T00:00:00Z"}
+	]}`, http.StatusCreated, &pushed)
+	if len(pushed.IDs) != 3 {
+		t.Error("Expected 3 ids, got", pushed.IDs)
+	}
+	do("POST", "/jobs", `{"jobs": []}`, http.StatusBadRequest, nil)
+	do("POST", "/jobs", `not json`, http.StatusBadRequest, nil)
+	do("POST", "/jobs", `{"jobs": [{"content": "`+strings.Repeat("x", 1<<10)+`"}]}`, http.StatusBadRequest, nil)
+	do("POST", "/jobs", `{"jobs": [{"id": "foo bar"}]}`, http.StatusBadRequest, nil)
+	do("POST", "/jobs", `{"jobs": [{"content": "foo", "strategy": 42}]}`, http.StatusBadRequest, nil)
+
+	var stats airq.Stats
+	do("GET", "/stats", "", http.StatusOK, &stats)
+	if stats.Pending != 3 || stats.Due != 2 {
+		t.Error("Expected 3 jobs pending and 2 due, got", stats)
+	}
+
+	var peeked struct{ Jobs []gateway.Job }
+	do("GET", "/jobs?limit=2", "", http.StatusOK, &peeked)
+	if len(peeked.Jobs) != 2 || peeked.Jobs[0].Subject != "greeting" || string(peeked.Jobs[1].Payload) != "\x00\x01\x02" {
+		t.Error("Expected to peek jobs 01 and 02, got", peeked.Jobs)
+	}
+
+	var job gateway.Job
+	do("GET", "/jobs/03", "", http.StatusOK, &job)
+	if job.Content != "baz" {
+		t.Error("Expected to get job 03, got", job)
+	}
+	do("GET", "/jobs/unknown", "", http.StatusNotFound, nil)
+
+	do("DELETE", "/jobs?id=01", "", http.StatusNoContent, nil)
+	do("DELETE", "/jobs", "", http.StatusBadRequest, nil)
+
+	var popped struct{ Jobs []gateway.Job }
+	do("POST", "/pop?limit=5&visibility=1m", "", http.StatusOK, &popped)
+	if len(popped.Jobs) != 1 || popped.Jobs[0].ID != "02" {
+		t.Error("Expected to reserve job 02, got", popped.Jobs)
+	}
+	do("POST", "/pop?limit=0", "", http.StatusBadRequest, nil)
+	do("POST", "/jobs/nack", `{"ids": ["02"], "delay": "1h"}`, http.StatusNoContent, nil)
+	do("POST", "/jobs/reschedule", `{"ids": ["02", "03"]}`, http.StatusBadRequest, nil)
+	do("POST", "/jobs/reschedule", `{"ids": ["02", "03"], "when": "2000-01-01T00:00:00Z"}`, http.StatusNoContent, nil)
+
+	do("POST", "/pop?limit=5", "", http.StatusOK, &popped)
+	if len(popped.Jobs) != 2 {
+		t.Error("Expected to pop rescheduled jobs, got", popped.Jobs)
+	}
+	do("GET", "/pop", "", http.StatusMethodNotAllowed, nil)
+}
+
+func TestGatewayUnavailable(t *testing.T) {
+	t.Parallel()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) { return redis.Dial("tcp", "127.0.0.1:1") }}
+	srv := httptest.NewServer(gateway.New(airq.New(randomName(), airq.WithPool(pool))))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Error("Expected an unreachable redis to be unavailable, got", res.StatusCode)
+	}
+}
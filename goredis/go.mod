@@ -0,0 +1,19 @@
+module github.com/jney/airq/goredis
+
+go 1.18
+
+require (
+	github.com/gomodule/redigo v1.8.5
+	github.com/jney/airq v0.0.0-20261018222753-83c6e99682ae
+	github.com/redis/go-redis/v9 v9.7.3
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/hashicorp/go-multierror v1.1.1 // indirect
+	github.com/rs/xid v1.3.0 // indirect
+	github.com/shamaton/msgpack/v2 v2.1.0 // indirect
+	github.com/shamaton/msgpackgen v0.3.0 // indirect
+)
@@ -0,0 +1,105 @@
+// Package goredis runs airq queues on a go-redis client, sharing its
+// connection pool:
+//
+//	q := airq.New("emails", airq.WithExecutor(goredis.New(client)))
+package goredis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/redis/go-redis/v9"
+)
+
+type executor struct {
+	c   redis.UniversalClient
+	ctx func() (context.Context, context.CancelFunc)
+}
+
+// Option configures an executor.
+type Option func(*executor)
+
+// WithContext runs each command with the context returned by f, canceled once
+// the command returns, e.g. to bound the operations of a queue:
+//
+//	goredis.New(client, goredis.WithContext(func() (context.Context, context.CancelFunc) {
+//		return context.WithTimeout(ctx, time.Second)
+//	}))
+func WithContext(f func() (context.Context, context.CancelFunc)) Option {
+	return func(e *executor) { e.ctx = f }
+}
+
+// WithTimeout runs each command with a deadline of d.
+func WithTimeout(d time.Duration) Option {
+	return WithContext(func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), d)
+	})
+}
+
+// New returns an executor running commands with c, a client, a cluster client
+// (queues being hash tagged, see airq.WithPrefix) or a failover client.
+func New(c redis.UniversalClient, opts ...Option) airq.Executor {
+	e := &executor{c: c, ctx: func() (context.Context, context.CancelFunc) {
+		return context.Background(), func() {}
+	}}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// NewContext returns an executor running commands with c and ctx, commands
+// failing once ctx is done. See WithContext for deadlines per command.
+func NewContext(ctx context.Context, c redis.UniversalClient) airq.Executor {
+	return New(c, WithContext(func() (context.Context, context.CancelFunc) { return ctx, func() {} }))
+}
+
+func (e *executor) Do(cmd string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := e.ctx()
+	defer cancel()
+	return reply(e.c.Do(ctx, append([]interface{}{cmd}, args...)...).Result())
+}
+
+func (e *executor) EvalSha(sha string, keys []string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := e.ctx()
+	defer cancel()
+	return reply(e.c.EvalSha(ctx, sha, keys, args...).Result())
+}
+
+func (e *executor) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := e.ctx()
+	defer cancel()
+	return reply(e.c.Eval(ctx, script, keys, args...).Result())
+}
+
+// reply converts a go-redis reply to a redigo one.
+func reply(v interface{}, err error) (interface{}, error) {
+	if err == redis.Nil {
+		return nil, redigo.ErrNil
+	}
+	var rErr redis.Error
+	if errors.As(err, &rErr) {
+		return nil, redigo.Error(rErr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convert(v), nil
+}
+
+// convert returns v with strings as []byte.
+func convert(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return []byte(v)
+	case []interface{}:
+		for i, e := range v {
+			v[i] = convert(e)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,127 @@
+package goredis_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"github.com/jney/airq/goredis"
+	"github.com/redis/go-redis/v9"
+)
+
+func setup(t *testing.T) (*airq.Queue, func()) {
+	t.Parallel()
+	c, err := redigo.Dial("tcp", "127.0.0.1:6379")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	q := airq.New(randomName(), airq.WithConn(c))
+	teardown := func() {
+		keys, _ := redigo.Strings(c.Do("KEYS", q.Name+"*"))
+		for _, k := range keys {
+			c.Do("DEL", k)
+		}
+		c.Do("SREM", "airq:queues", q.Name)
+		c.Close()
+	}
+	return q, teardown
+}
+
+func randomName() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+func TestGoRedis(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+	defer client.Close()
+	gq := airq.New(q.Name, airq.WithExecutor(goredis.New(client)))
+
+	ids, err := gq.Push(&airq.Job{ID: "1", Content: "first"}, &airq.Job{ID: "2", Content: "second", Headers: map[string]string{"k": "v"}})
+	if err != nil || len(ids) != 2 {
+		t.Error("Expected 2 jobs pushed, got", ids, err)
+		t.FailNow()
+	}
+	// jobs pushed with go-redis are read with redigo
+	if pending, _ := q.Pending(); pending != 2 {
+		t.Error("Expected 2 jobs pending, got", pending)
+	}
+	if stats, err := gq.Stats(); err != nil || stats.Pending != 2 {
+		t.Error("Expected 2 jobs pending, got", stats, err)
+	}
+	if j, err := gq.Get("2"); err != nil || j == nil || j.Headers["k"] != "v" {
+		t.Error("Expected job 2, got", j, err)
+	}
+	if j, err := gq.Get("missing"); err != nil || j != nil {
+		t.Error("Expected no job, got", j, err)
+	}
+	if jobs, err := gq.Peek(5); err != nil || len(jobs) != 2 {
+		t.Error("Expected 2 jobs peeked, got", jobs, err)
+	}
+	jobs, err := gq.Reserve(1, time.Minute)
+	if err != nil || len(jobs) != 1 || jobs[0].Content != "first" {
+		t.Error("Expected the first job reserved, got", jobs, err)
+		t.FailNow()
+	}
+	if err := gq.Ack("1"); err != nil {
+		t.Error(err)
+	}
+	if err := gq.Ack("1"); !errors.Is(err, airq.ErrNotReserved) {
+		t.Error("Expected ErrNotReserved, got", err)
+	}
+	if err := gq.Reschedule(time.Now().Add(-time.Second), "2"); err != nil {
+		t.Error(err)
+	}
+	if j, err := gq.Pop(); err != nil || j == nil || j.Content != "second" {
+		t.Error("Expected the second job popped, got", j, err)
+	}
+	infos, err := airq.ListQueues(nil, airq.WithExecutor(goredis.New(client)))
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, info := range infos {
+		found = found || info.Name == q.Name
+	}
+	if !found {
+		t.Error("Expected", q.Name, "to be listed, got", infos)
+	}
+}
+
+func TestContext(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+	defer client.Close()
+
+	var calls int
+	gq := airq.New(q.Name, airq.WithExecutor(goredis.New(client, goredis.WithContext(func() (context.Context, context.CancelFunc) {
+		calls++
+		return context.WithTimeout(context.Background(), time.Second)
+	}))))
+	if _, err := gq.Push(&airq.Job{Content: "first"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := gq.Pop(); err != nil || calls < 2 {
+		t.Error("Expected a context per command, got", calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := airq.New(q.Name, airq.WithExecutor(goredis.NewContext(ctx, client))).Pending(); !errors.Is(err, context.Canceled) {
+		t.Error("Expected context.Canceled, got", err)
+	}
+	expired := goredis.New(client, goredis.WithTimeout(-time.Second))
+	if _, err := airq.New(q.Name, airq.WithExecutor(expired)).Pending(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected context.DeadlineExceeded, got", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Package convert converts jobs between the queue and the gRPC messages,
+// shared by the server and the client so both sides carry the same fields.
+package convert
+
+import (
+	"time"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/job"
+	"google.golang.org/protobuf/proto"
+)
+
+// ToProto converts a queue job to its message, without execution time if not set.
+func ToProto(j *airq.Job) *job.Job {
+	pj := &job.Job{
+		Id:       j.ID,
+		Content:  j.Content,
+		Headers:  j.Headers,
+		Payload:  j.Payload,
+		Strategy: int32(j.Strategy),
+		Subject:  j.Subject,
+	}
+	if !j.When.IsZero() {
+		pj.When = proto.Int64(j.When.UnixNano())
+	}
+	return pj
+}
+
+// FromProto converts a message to a queue job, scheduled now by the queue if
+// its execution time is not set.
+func FromProto(pj *job.Job) *airq.Job {
+	j := &airq.Job{
+		ID:       pj.GetId(),
+		Content:  pj.GetContent(),
+		Headers:  pj.GetHeaders(),
+		Payload:  pj.GetPayload(),
+		Strategy: airq.Strategy(pj.GetStrategy()),
+		Subject:  pj.GetSubject(),
+	}
+	if pj.When != nil {
+		j.When = time.Unix(0, pj.GetWhen())
+	}
+	return j
+}
+
+// ToList converts queue jobs to a message.
+func ToList(jobs []*airq.Job) *job.JobList {
+	l := new(job.JobList)
+	for _, j := range jobs {
+		l.Jobs = append(l.Jobs, ToProto(j))
+	}
+	return l
+}
+
+// FromList converts the jobs of a message to queue jobs.
+func FromList(l *job.JobList) []*airq.Job {
+	var jobs []*airq.Job
+	for _, j := range l.GetJobs() {
+		jobs = append(jobs, FromProto(j))
+	}
+	return jobs
+}
+
+// IDs returns the ids of a message.
+func IDs(l []*job.Id) []string {
+	var ids []string
+	for _, i := range l {
+		ids = append(ids, i.GetId())
+	}
+	return ids
+}
+
+// IDList converts ids to a message routed to queue.
+func IDList(queue string, ids []string) *job.IdList {
+	l := &job.IdList{Queue: queue}
+	for _, i := range ids {
+		l.Ids = append(l.Ids, &job.Id{Id: i})
+	}
+	return l
+}
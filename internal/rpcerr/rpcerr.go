@@ -0,0 +1,136 @@
+// Package rpcerr converts queue errors to grpc status errors and back, so that
+// errors returned by the client match the airq sentinel errors with errors.Is.
+package rpcerr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/jney/airq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// domain identifies the error details set by airq servers.
+const domain = "airq"
+
+var sentinels = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{airq.ErrLimitZero, codes.InvalidArgument, "LIMIT_ZERO"},
+	{airq.ErrNoIDs, codes.InvalidArgument, "NO_IDS"},
+	{airq.ErrNoJobs, codes.InvalidArgument, "NO_JOBS"},
+	{airq.ErrPayloadTooLarge, codes.InvalidArgument, "PAYLOAD_TOO_LARGE"},
+	{airq.ErrNotFound, codes.NotFound, "NOT_FOUND"},
+	{airq.ErrNotReserved, codes.FailedPrecondition, "NOT_RESERVED"},
+	{airq.ErrUnavailable, codes.Unavailable, "UNAVAILABLE"},
+}
+
+// Error is an error received from a server, matching its airq sentinel error if any.
+type Error struct {
+	status *status.Status
+	err    error
+}
+
+func (e *Error) Error() string { return e.status.Message() }
+
+// Unwrap returns the sentinel error.
+func (e *Error) Unwrap() error { return e.err }
+
+// GRPCStatus returns the status received, for status.Code and status.FromError.
+func (e *Error) GRPCStatus() *status.Status { return e.status }
+
+// ToStatus converts err to a status error, with its sentinel error as reason.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code, reason := classify(err)
+	st := status.New(code, err.Error())
+	if reason == "" {
+		return st.Err()
+	}
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: domain}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+// Code returns the code of err, a queue error being converted as by ToStatus,
+// e.g. for the HTTP gateway to return the same errors.
+func Code(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	code, _ := classify(err)
+	return code
+}
+
+// classify returns the code of err and the reason of its sentinel error, if any.
+func classify(err error) (codes.Code, string) {
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			return s.code, s.reason
+		}
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled, ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded, ""
+	case unavailable(err):
+		return codes.Unavailable, "UNAVAILABLE"
+	}
+	return codes.Internal, ""
+}
+
+// FromStatus converts a status error to an Error wrapping its sentinel error.
+func FromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+	e := &Error{status: st}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != domain {
+			continue
+		}
+		for _, s := range sentinels {
+			if s.reason == info.GetReason() {
+				e.err = s.err
+			}
+		}
+	}
+	if e.err == nil {
+		switch st.Code() {
+		case codes.Unavailable:
+			e.err = airq.ErrUnavailable
+		case codes.Canceled:
+			e.err = context.Canceled
+		case codes.DeadlineExceeded:
+			e.err = context.DeadlineExceeded
+		default:
+			return err
+		}
+	}
+	return e
+}
+
+// unavailable reports whether err comes from an unreachable redis server.
+func unavailable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, redis.ErrPoolExhausted)
+}
@@ -0,0 +1,68 @@
+// Package validate checks the jobs pushed through the gRPC server and the
+// HTTP gateway.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jney/airq"
+)
+
+const (
+	// DefaultMaxBatchSize is the maximum count of jobs pushed at once by default.
+	DefaultMaxBatchSize = 1000
+	// MaxIDLength is the maximum length of job ids set by clients.
+	MaxIDLength = 256
+)
+
+// ErrInvalid is returned for pushed jobs that aren't valid.
+var ErrInvalid = errors.New("invalid request")
+
+// Limits bounds the pushed jobs, unlimited if 0.
+type Limits struct {
+	MaxBatchSize int
+	MaxJobSize   int
+}
+
+// Jobs checks pushed jobs, returning ErrInvalid or airq.ErrPayloadTooLarge.
+func (l Limits) Jobs(jobs []*airq.Job) error {
+	if l.MaxBatchSize > 0 && len(jobs) > l.MaxBatchSize {
+		return fmt.Errorf("%w: %d jobs pushed at once, limit is %d", ErrInvalid, len(jobs), l.MaxBatchSize)
+	}
+	for i, j := range jobs {
+		switch j.Strategy {
+		case airq.UpdateStrategy, airq.CreateStrategy:
+		case airq.KeepStrategy:
+			// not implemented by the queue, it would behave as UpdateStrategy
+			return fmt.Errorf("%w: job %d: keep strategy not supported", ErrInvalid, i)
+		default:
+			return fmt.Errorf("%w: job %d: unknown strategy %d", ErrInvalid, i, j.Strategy)
+		}
+		if err := ID(j.ID); err != nil {
+			return fmt.Errorf("%w: job %d: %v", ErrInvalid, i, err)
+		}
+		if size := len(j.Content) + len(j.Payload); l.MaxJobSize > 0 && size > l.MaxJobSize {
+			return fmt.Errorf("%w: job %d of %d bytes, limit is %d", airq.ErrPayloadTooLarge, i, size, l.MaxJobSize)
+		}
+	}
+	return nil
+}
+
+// ID checks an id set by a client, empty ids being generated by the queue.
+func ID(id string) error {
+	if len(id) > MaxIDLength {
+		return fmt.Errorf("id of %d bytes, limit is %d", len(id), MaxIDLength)
+	}
+	if !utf8.ValidString(id) {
+		return fmt.Errorf("id %q is not valid utf-8", id)
+	}
+	for _, r := range id {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return fmt.Errorf("id %q contains spaces or control characters", id)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package airq
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"strconv"
 	"time"
@@ -22,49 +25,94 @@ const (
 
 // Job is the struct of job in queue
 type Job struct {
-	CompressedContent string    `msgpack:"content"`
-	Content           string    `msgpack:"-"`
-	ID                string    `msgpack:"id"`
-	Strategy          Strategy  `msgpack:"-"`
-	Subject           string    `msgpack:"subject"`
-	When              time.Time `msgpack:"-"`
-	WhenUnixNano      int64     `msgpack:"when"`
+	Blob              string            `msgpack:"blob"` // key of the job offloaded to a BlobStore, set internally
+	CompressedContent string            `msgpack:"content"`
+	CompressedPayload []byte            `msgpack:"payload"`
+	Content           string            `msgpack:"-"`
+	Headers           map[string]string `msgpack:"headers"` // metadata (trace id, tenant, ...), not encrypted
+	ID                string            `msgpack:"id"`
+	Key               string            `msgpack:"key"` // id of the key the contents are encrypted with, set internally
+	Payload           []byte            `msgpack:"-"`   // binary content, stored as is (no string conversion)
+	Strategy          Strategy          `msgpack:"-"`
+	Subject           string            `msgpack:"subject"`
+	When              time.Time         `msgpack:"-"`
+	WhenUnixNano      int64             `msgpack:"when"`
 }
 
-func newJobFromString(in string) (*Job, error) {
+func newJobFromBytes(in []byte, keys *KeyRing) (*Job, error) {
 	var j Job
-	if err := msgpack.Unmarshal([]byte(in), &j); err != nil {
+	if err := msgpack.Unmarshal(in, &j); err != nil {
 		return nil, err
 	}
-	j.Content = uncompress(j.CompressedContent)
+	if j.Key != "" {
+		if keys == nil {
+			return nil, fmt.Errorf("job %s is encrypted with key %q, no key ring provided", j.ID, j.Key)
+		}
+		if err := keys.open(&j); err != nil {
+			return nil, err
+		}
+	}
+	j.When = time.Unix(0, j.WhenUnixNano)
+	j.Content = string(uncompress([]byte(j.CompressedContent)))
+	j.Payload = uncompress(j.CompressedPayload)
 	return &j, nil
 }
 
-func compress(in string) string {
+func compress(in []byte) []byte {
 	var b bytes.Buffer
 	gz := gzip.NewWriter(&b)
-	gz.Write([]byte(in))
+	gz.Write(in)
 	gz.Flush()
 	gz.Close()
-	return b.String()
+	return b.Bytes()
+}
+
+func uncompress(in []byte) []byte {
+	if len(in) == 0 {
+		return nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(in))
+	if err != nil {
+		return nil
+	}
+	b, _ := ioutil.ReadAll(r)
+	return b
 }
 
-func uncompress(in string) string {
-	rdata := bytes.NewReader([]byte(in))
-	r, _ := gzip.NewReader(rdata)
-	s, _ := ioutil.ReadAll(r)
-	return string(s)
+// size returns the size of the job contents, before compression.
+func (j *Job) size() int {
+	return len(j.Content) + len(j.Payload)
 }
 
 func (j *Job) generateID() string {
 	if j.Strategy == CreateStrategy {
 		return xid.New().String()
 	}
-	return strconv.FormatUint(xxhash.Sum64String(j.Content), 10)
+	d := xxhash.New()
+	j.writeContents(d)
+	return strconv.FormatUint(d.Sum64(), 10)
+}
+
+// writeContents writes the contents identifying the job to w. Jobs without
+// payload are identified by their content only, as before payloads existed,
+// otherwise content is length-prefixed, ("ab", "c") and ("a", "bc") being
+// different jobs.
+func (j *Job) writeContents(w io.Writer) {
+	if len(j.Payload) == 0 {
+		io.WriteString(w, j.Content)
+		return
+	}
+	var n [binary.MaxVarintLen64]byte
+	w.Write(n[:binary.PutUvarint(n[:], uint64(len(j.Content)))])
+	io.WriteString(w, j.Content)
+	w.Write(j.Payload)
 }
 
 func (j *Job) setDefaults() {
-	j.CompressedContent = compress(j.Content)
+	j.CompressedContent = string(compress([]byte(j.Content)))
+	if len(j.Payload) > 0 {
+		j.CompressedPayload = compress(j.Payload)
+	}
 	if j.When.IsZero() {
 		j.When = time.Now()
 	}
@@ -74,8 +122,30 @@ func (j *Job) setDefaults() {
 	}
 }
 
-func (j *Job) String() string {
+// Bytes returns the msgpack representation of the job as stored in redis.
+func (j *Job) Bytes() []byte {
+	b, _ := j.encode(nil)
+	return b
+}
+
+// encode returns the msgpack representation of the job, its contents
+// encrypted with the current key of keys if not nil.
+func (j *Job) encode(keys *KeyRing) ([]byte, error) {
+	if keys != nil && j.ID == "" && j.Strategy != CreateStrategy {
+		// ids stored in clear must not reveal the contents
+		j.ID = keys.jobID(j)
+	}
 	j.setDefaults()
-	b, _ := msgpack.Marshal(j)
-	return string(b)
+	if keys == nil {
+		return msgpack.Marshal(j)
+	}
+	e := *j
+	if err := keys.seal(&e); err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(&e)
+}
+
+func (j *Job) String() string {
+	return string(j.Bytes())
 }
@@ -20,6 +20,16 @@ const _ = grpc.SupportPackageIsVersion7
 type JobsClient interface {
 	Push(ctx context.Context, in *JobList, opts ...grpc.CallOption) (*IdList, error)
 	Remove(ctx context.Context, in *IdList, opts ...grpc.CallOption) (*Void, error)
+	Pop(ctx context.Context, in *PopRequest, opts ...grpc.CallOption) (*JobList, error)
+	Pending(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*Count, error)
+	Peek(ctx context.Context, in *PeekRequest, opts ...grpc.CallOption) (*JobList, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Job, error)
+	Ack(ctx context.Context, in *IdList, opts ...grpc.CallOption) (*Void, error)
+	Nack(ctx context.Context, in *NackRequest, opts ...grpc.CallOption) (*Void, error)
+	Reschedule(ctx context.Context, in *RescheduleRequest, opts ...grpc.CallOption) (*Void, error)
+	Subscribe(ctx context.Context, opts ...grpc.CallOption) (Jobs_SubscribeClient, error)
+	Stats(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*QueueStats, error)
+	Purge(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*Count, error)
 }
 
 type jobsClient struct {
@@ -48,12 +58,134 @@ func (c *jobsClient) Remove(ctx context.Context, in *IdList, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *jobsClient) Pop(ctx context.Context, in *PopRequest, opts ...grpc.CallOption) (*JobList, error) {
+	out := new(JobList)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Pop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Pending(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*Count, error) {
+	out := new(Count)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Pending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Peek(ctx context.Context, in *PeekRequest, opts ...grpc.CallOption) (*JobList, error) {
+	out := new(JobList)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Peek", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Ack(ctx context.Context, in *IdList, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Nack(ctx context.Context, in *NackRequest, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Nack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Reschedule(ctx context.Context, in *RescheduleRequest, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Reschedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (Jobs_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Jobs_ServiceDesc.Streams[0], "/job.Jobs/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobsSubscribeClient{stream}
+	return x, nil
+}
+
+type Jobs_SubscribeClient interface {
+	Send(*SubscribeRequest) error
+	Recv() (*JobList, error)
+	grpc.ClientStream
+}
+
+type jobsSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobsSubscribeClient) Send(m *SubscribeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *jobsSubscribeClient) Recv() (*JobList, error) {
+	m := new(JobList)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *jobsClient) Stats(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*QueueStats, error) {
+	out := new(QueueStats)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) Purge(ctx context.Context, in *Queue, opts ...grpc.CallOption) (*Count, error) {
+	out := new(Count)
+	err := c.cc.Invoke(ctx, "/job.Jobs/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // JobsServer is the server API for Jobs service.
 // All implementations must embed UnimplementedJobsServer
 // for forward compatibility
 type JobsServer interface {
 	Push(context.Context, *JobList) (*IdList, error)
 	Remove(context.Context, *IdList) (*Void, error)
+	Pop(context.Context, *PopRequest) (*JobList, error)
+	Pending(context.Context, *Queue) (*Count, error)
+	Peek(context.Context, *PeekRequest) (*JobList, error)
+	Get(context.Context, *GetRequest) (*Job, error)
+	Ack(context.Context, *IdList) (*Void, error)
+	Nack(context.Context, *NackRequest) (*Void, error)
+	Reschedule(context.Context, *RescheduleRequest) (*Void, error)
+	Subscribe(Jobs_SubscribeServer) error
+	Stats(context.Context, *Queue) (*QueueStats, error)
+	Purge(context.Context, *Queue) (*Count, error)
 	mustEmbedUnimplementedJobsServer()
 }
 
@@ -67,6 +199,36 @@ func (UnimplementedJobsServer) Push(context.Context, *JobList) (*IdList, error)
 func (UnimplementedJobsServer) Remove(context.Context, *IdList) (*Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
 }
+func (UnimplementedJobsServer) Pop(context.Context, *PopRequest) (*JobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pop not implemented")
+}
+func (UnimplementedJobsServer) Pending(context.Context, *Queue) (*Count, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pending not implemented")
+}
+func (UnimplementedJobsServer) Peek(context.Context, *PeekRequest) (*JobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Peek not implemented")
+}
+func (UnimplementedJobsServer) Get(context.Context, *GetRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedJobsServer) Ack(context.Context, *IdList) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
+func (UnimplementedJobsServer) Nack(context.Context, *NackRequest) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nack not implemented")
+}
+func (UnimplementedJobsServer) Reschedule(context.Context, *RescheduleRequest) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reschedule not implemented")
+}
+func (UnimplementedJobsServer) Subscribe(Jobs_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedJobsServer) Stats(context.Context, *Queue) (*QueueStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedJobsServer) Purge(context.Context, *Queue) (*Count, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
 func (UnimplementedJobsServer) mustEmbedUnimplementedJobsServer() {}
 
 // UnsafeJobsServer may be embedded to opt out of forward compatibility for this service.
@@ -116,6 +278,194 @@ func _Jobs_Remove_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Jobs_Pop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Pop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Pop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Pop(ctx, req.(*PopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Pending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Queue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Pending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Pending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Pending(ctx, req.(*Queue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Peek_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeekRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Peek(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Peek",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Peek(ctx, req.(*PeekRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Ack(ctx, req.(*IdList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Nack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Nack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Nack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Nack(ctx, req.(*NackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Reschedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Reschedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Reschedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Reschedule(ctx, req.(*RescheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JobsServer).Subscribe(&jobsSubscribeServer{stream})
+}
+
+type Jobs_SubscribeServer interface {
+	Send(*JobList) error
+	Recv() (*SubscribeRequest, error)
+	grpc.ServerStream
+}
+
+type jobsSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobsSubscribeServer) Send(m *JobList) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *jobsSubscribeServer) Recv() (*SubscribeRequest, error) {
+	m := new(SubscribeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Jobs_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Queue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Stats(ctx, req.(*Queue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Queue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/job.Jobs/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Purge(ctx, req.(*Queue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Jobs_ServiceDesc is the grpc.ServiceDesc for Jobs service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -131,7 +481,50 @@ var Jobs_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Remove",
 			Handler:    _Jobs_Remove_Handler,
 		},
+		{
+			MethodName: "Pop",
+			Handler:    _Jobs_Pop_Handler,
+		},
+		{
+			MethodName: "Pending",
+			Handler:    _Jobs_Pending_Handler,
+		},
+		{
+			MethodName: "Peek",
+			Handler:    _Jobs_Peek_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Jobs_Get_Handler,
+		},
+		{
+			MethodName: "Ack",
+			Handler:    _Jobs_Ack_Handler,
+		},
+		{
+			MethodName: "Nack",
+			Handler:    _Jobs_Nack_Handler,
+		},
+		{
+			MethodName: "Reschedule",
+			Handler:    _Jobs_Reschedule_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Jobs_Stats_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _Jobs_Purge_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Jobs_Subscribe_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "job/job.proto",
 }
@@ -4,10 +4,10 @@ import "testing"
 
 func TestCompress(t *testing.T) {
 	t.Parallel()
-	if out := uncompress(compress("")); out != "" {
+	if out := uncompress(compress(nil)); len(out) != 0 {
 		t.Errorf("compression failed %s != \"\"", out)
 	}
-	if out := uncompress(compress("test")); out != "test" {
+	if out := uncompress(compress([]byte("test"))); string(out) != "test" {
 		t.Errorf("compression failed %s != \"test\"", out)
 	}
 }
@@ -23,3 +23,19 @@ func TestSetDefaults(t *testing.T) {
 		t.Error("job.When should be now")
 	}
 }
+
+func TestGenerateID(t *testing.T) {
+	t.Parallel()
+	a := &Job{Content: "ab", Payload: []byte("c")}
+	b := &Job{Content: "a", Payload: []byte("bc")}
+	if a.generateID() == b.generateID() {
+		t.Error("Expected different ids for different contents and payloads")
+	}
+	if a.generateID() != (&Job{Content: "ab", Payload: []byte("c")}).generateID() {
+		t.Error("Expected the same id for the same contents and payloads")
+	}
+	// ids of jobs without payload are kept across versions
+	if id := (&Job{Content: "test"}).generateID(); id != "5754696928334414137" {
+		t.Error("Expected the id of content only jobs to be unchanged, got", id)
+	}
+}
@@ -0,0 +1,24 @@
+package airq
+
+// WithPrefix stores the queue under keys starting with prefix, its name being
+// a hash tag ("prefix{name}", "prefix{name}:values", ...) so that all its keys
+// are in the same Redis Cluster slot. WithPrefix("") only adds the hash tag.
+// Queues created without it keep the original layout ("name", "name:values", ...).
+func WithPrefix(prefix string) Option {
+	return func(q *Queue) { q.prefix, q.hashTag = prefix, true }
+}
+
+// key returns the key of the sorted set of job ids, the other keys of the
+// queue starting with it.
+func (q *Queue) key() string {
+	if !q.hashTag {
+		return q.Name
+	}
+	return q.prefix + "{" + q.Name + "}"
+}
+
+// valuesKey returns the key of the hash of job values.
+func (q *Queue) valuesKey() string { return q.key() + ":values" }
+
+// reservedKey returns the key of the sorted set of reserved job ids.
+func (q *Queue) reservedKey() string { return q.key() + ":reserved" }
@@ -0,0 +1,212 @@
+package airq
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// memoryStore is a Store keeping jobs in memory.
+type memoryStore struct {
+	sync.Mutex
+	jobs map[string]*memoryJob
+}
+
+type memoryJob struct {
+	value    []byte
+	pending  bool
+	when     int64
+	reserved bool
+	deadline int64
+}
+
+// NewMemoryStore returns a Store keeping jobs in memory, for tests or
+// embedded queues. Jobs are lost when the process exits.
+func NewMemoryStore() Store {
+	return &memoryStore{jobs: make(map[string]*memoryJob)}
+}
+
+func (s *memoryStore) Push(jobs ...StoredJob) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	var replaced [][]byte
+	for _, j := range jobs {
+		job, ok := s.jobs[j.ID]
+		if ok {
+			replaced = append(replaced, job.value)
+		} else {
+			job = &memoryJob{}
+			s.jobs[j.ID] = job
+		}
+		job.value, job.pending, job.when = j.Value, true, j.When
+	}
+	return replaced, nil
+}
+
+func (s *memoryStore) Pop(now int64, limit int) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	ids := s.pending(true, now, 0, limit)
+	values := make([][]byte, len(ids))
+	for i, id := range ids {
+		values[i] = s.jobs[id].value
+		delete(s.jobs, id)
+	}
+	return values, nil
+}
+
+func (s *memoryStore) Reserve(now int64, limit int, deadline int64) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	for _, j := range s.jobs {
+		if j.reserved && j.deadline <= now {
+			j.reserved, j.pending, j.when = false, true, now
+		}
+	}
+	ids := s.pending(true, now, 0, limit)
+	values := make([][]byte, len(ids))
+	for i, id := range ids {
+		j := s.jobs[id]
+		j.pending, j.reserved, j.deadline = false, true, deadline
+		values[i] = j.value
+	}
+	return values, nil
+}
+
+func (s *memoryStore) Ack(ids ...string) (int, [][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	var removed [][]byte
+	for _, id := range ids {
+		if j, ok := s.jobs[id]; ok && j.reserved {
+			n++
+			j.reserved = false
+			if !j.pending {
+				removed = append(removed, j.value)
+				delete(s.jobs, id)
+			}
+		}
+	}
+	return n, removed, nil
+}
+
+func (s *memoryStore) Nack(when int64, ids ...string) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for _, id := range ids {
+		if j, ok := s.jobs[id]; ok && j.reserved {
+			n++
+			j.reserved, j.pending, j.when = false, true, when
+		}
+	}
+	return n, nil
+}
+
+func (s *memoryStore) List(offset, limit int) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	ids := s.pending(false, 0, offset, limit)
+	values := make([][]byte, len(ids))
+	for i, id := range ids {
+		values[i] = s.jobs[id].value
+	}
+	return values, nil
+}
+
+func (s *memoryStore) Get(id string) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	if j, ok := s.jobs[id]; ok {
+		return j.value, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Reschedule(id string, old, value []byte, when int64) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	j, ok := s.jobs[id]
+	if !ok || !j.pending {
+		return false, ErrNotFound
+	}
+	if !bytes.Equal(j.value, old) {
+		return false, nil
+	}
+	j.value, j.when = value, when
+	return true, nil
+}
+
+func (s *memoryStore) Remove(ids ...string) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	var removed [][]byte
+	for _, id := range ids {
+		if j, ok := s.jobs[id]; ok {
+			removed = append(removed, j.value)
+			delete(s.jobs, id)
+		}
+	}
+	return removed, nil
+}
+
+func (s *memoryStore) Count() (int64, error) {
+	stats, err := s.Stats(0)
+	return stats.Pending, err
+}
+
+func (s *memoryStore) Stats(now int64) (Stats, error) {
+	s.Lock()
+	defer s.Unlock()
+	var stats Stats
+	for _, j := range s.jobs {
+		if j.pending {
+			stats.Pending++
+			if j.when <= now {
+				stats.Due++
+			}
+		}
+		if j.reserved {
+			stats.Reserved++
+		}
+	}
+	return stats, nil
+}
+
+func (s *memoryStore) Purge() ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	values := make([][]byte, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		values = append(values, j.value)
+	}
+	s.jobs = make(map[string]*memoryJob)
+	return values, nil
+}
+
+// pending returns the ids of pending jobs by time then id, as a sorted set,
+// only those due at now if due is true.
+func (s *memoryStore) pending(due bool, now int64, offset, limit int) []string {
+	var ids []string
+	for id, j := range s.jobs {
+		if j.pending && (!due || j.when <= now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		ja, jb := s.jobs[ids[a]], s.jobs[ids[b]]
+		if ja.when != jb.when {
+			return ja.when < jb.when
+		}
+		return ids[a] < ids[b]
+	})
+	if offset >= len(ids) {
+		return nil
+	}
+	ids = ids[offset:]
+	if limit < len(ids) {
+		ids = ids[:limit]
+	}
+	return ids
+}
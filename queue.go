@@ -1,11 +1,13 @@
 package airq
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/hashicorp/go-multierror"
+	"github.com/shamaton/msgpackgen/msgpack"
 )
 
 // Queue holds a reference to a redis connection and a queue name.
@@ -13,6 +15,20 @@ type Queue struct {
 	conn redis.Conn
 	Name string
 	Pool *redis.Pool
+
+	blobs            BlobStore
+	clock            func() time.Time
+	hashTag          bool
+	keys             *KeyRing
+	maxPayloadSize   int
+	namespace        string
+	offloadThreshold int
+	executor         Executor
+	prefix           string
+	provider         ConnProvider
+	registered       uint32
+	store            Store
+	undecodableDelay time.Duration
 }
 
 type LoopOptions struct {
@@ -25,9 +41,39 @@ type Option func(*Queue)
 func WithConn(c redis.Conn) Option  { return func(q *Queue) { q.conn = c } }
 func WithPool(p *redis.Pool) Option { return func(q *Queue) { q.Pool = p } }
 
-func (q *Queue) Conn() (redis.Conn, bool) {
+// WithClock makes the queue read the time with now instead of time.Now, e.g.
+// to fast-forward scheduled jobs in tests.
+func WithClock(now func() time.Time) Option { return func(q *Queue) { q.clock = now } }
+
+// DefaultUndecodableDelay is the delay before jobs that can't be decoded are
+// popped again.
+const DefaultUndecodableDelay = time.Minute
+
+// WithUndecodableDelay sets the delay before jobs that can't be decoded, e.g.
+// encrypted with a key missing from the key ring, are popped again,
+// DefaultUndecodableDelay by default. The other jobs are popped meanwhile.
+func WithUndecodableDelay(d time.Duration) Option {
+	return func(q *Queue) { q.undecodableDelay = d }
+}
+
+func (q *Queue) now() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+	return time.Now()
+}
+
+// Conn returns a redigo connection of the queue and whether it must be closed.
+// Its commands fail with ErrNoConnection if the queue has none.
+func (q *Queue) Conn() (redis.Conn, bool) { return q.connFor(q.key()) }
+
+// connFor returns a connection to the node serving key, and whether it must be closed.
+func (q *Queue) connFor(key string) (redis.Conn, bool) {
+	if q.provider != nil {
+		return q.provider.Get(key), true
+	}
 	if q.conn == nil && q.Pool == nil {
-		panic("no connection defined")
+		return errorConn{ErrNoConnection}, false
 	}
 	if q.Pool != nil {
 		return q.Pool.Get(), true
@@ -50,16 +96,21 @@ func (q *Queue) Loop(cb func([]*Job, error), opts *LoopOptions) {
 		jobs, err := q.PopJobs(opts.Size)
 		if err != nil || len(jobs) > 0 {
 			cb(jobs, err)
-			continue
 		}
-		time.Sleep(opts.Sleep)
+		// errors without jobs, e.g. undecodable jobs pushed back, wait as well
+		if len(jobs) == 0 {
+			time.Sleep(opts.Sleep)
+		}
 	}
 }
 
 // New defines a new Queue
-func New(name string, opt Option) *Queue {
-	q := &Queue{Name: name}
-	opt(q)
+func New(name string, opts ...Option) *Queue {
+	q := &Queue{Name: name, undecodableDelay: DefaultUndecodableDelay}
+	q.store = redisStore{q}
+	for _, opt := range opts {
+		opt(q)
+	}
 	return q
 }
 
@@ -68,31 +119,77 @@ func New(name string, opt Option) *Queue {
 // as jobs are popped in order of due date.
 func (q *Queue) Push(jobs ...*Job) (ids []string, err error) {
 	if len(jobs) == 0 {
-		return ids, fmt.Errorf("no jobs provided")
+		return ids, ErrNoJobs
 	}
-	c, managed := q.Conn()
-	if managed {
-		defer c.Close()
+	if q.maxPayloadSize > 0 {
+		for _, j := range jobs {
+			if size := j.size(); size > q.maxPayloadSize {
+				return ids, fmt.Errorf("%w: job of %d bytes, limit is %d", ErrPayloadTooLarge, size, q.maxPayloadSize)
+			}
+		}
 	}
-	keysAndArgs := redis.Args{q.Name}
+	stored := make([]StoredJob, 0, len(jobs))
+	var blobKeys []string
 	for _, j := range jobs {
-		keysAndArgs = keysAndArgs.AddFlat(j.String())
+		if j.When.IsZero() {
+			j.When = q.now()
+		}
+		b, err := j.encode(q.keys)
+		if err != nil {
+			q.deleteBlobs(blobKeys)
+			return nil, err
+		}
+		if q.blobs != nil && j.size() > q.offloadThreshold {
+			var key string
+			if b, key, err = q.offload(j, b); err != nil {
+				q.deleteBlobs(blobKeys)
+				return nil, err
+			}
+			blobKeys = append(blobKeys, key)
+		}
+		stored = append(stored, StoredJob{ID: j.ID, When: j.WhenUnixNano, Value: b})
 		ids = append(ids, j.ID)
 	}
-	ok, err := redis.Int(pushScript.Do(c, keysAndArgs...))
-	if err == nil && ok != 1 {
-		err = fmt.Errorf("can't add all jobs %v to queue %s", jobs, q.Name)
+	if _, ok := q.store.(redisStore); ok {
+		if err := q.Register(); err != nil {
+			q.deleteBlobs(blobKeys)
+			return nil, err
+		}
+	}
+	replaced, err := q.store.Push(stored...)
+	if err != nil {
+		q.deleteBlobs(blobKeys)
+		return ids, err
 	}
-	return ids, err
+	return ids, q.dropBlobs(replaced)
 }
 
 // Pending returns the count of jobs pending, including scheduled jobs that are not due yet.
 func (q *Queue) Pending() (int64, error) {
-	c, managed := q.Conn()
-	if managed {
-		defer c.Close()
+	return q.store.Count()
+}
+
+// Stats holds the job counters of a queue.
+type Stats struct {
+	Pending  int64 `json:"pending"`  // jobs pending, including scheduled jobs that are not due yet
+	Due      int64 `json:"due"`      // pending jobs due now
+	Reserved int64 `json:"reserved"` // jobs reserved and not acknowledged yet
+}
+
+// Stats returns the job counters of the queue.
+func (q *Queue) Stats() (Stats, error) {
+	return q.store.Stats(q.now().UnixNano())
+}
+
+// Purge removes all the jobs of the queue, pending or reserved, and returns
+// their count. The queue is still listed by ListQueues until unregistered
+// (see Unregister).
+func (q *Queue) Purge() (int64, error) {
+	removed, err := q.store.Purge()
+	if err != nil {
+		return 0, err
 	}
-	return redis.Int64(c.Do("ZCARD", q.Name))
+	return int64(len(removed)), q.dropBlobs(removed)
 }
 
 // Pop removes and returns a single job from the queue. Safe for concurrent use
@@ -110,44 +207,245 @@ func (q *Queue) Pop() (*Job, error) {
 
 // PopJobs returns multiple jobs from the queue. Safe for concurrent use
 // (multiple goroutines must use their own Queue objects and redis connections)
+// Jobs that can't be decoded, e.g. encrypted with a key missing from the key
+// ring, are pushed back unchanged, to be popped again after the undecodable
+// delay (see WithUndecodableDelay), and reported in the error.
 func (q *Queue) PopJobs(limit int) (res []*Job, err error) {
-	if limit == 0 {
-		return res, fmt.Errorf("limit 0")
+	if limit <= 0 {
+		return res, ErrLimitZero
 	}
-	c, managed := q.Conn()
-	if managed {
-		defer c.Close()
-	}
-	redisRes, err := redis.Strings(popJobsScript.Do(
-		c, q.Name, time.Now().UnixNano(), limit,
-	))
+	now := q.now()
+	redisRes, err := q.store.Pop(now.UnixNano(), limit)
 	if err != nil {
 		return nil, err
 	}
 	var mErr error
+	var failed []StoredJob
+	var popped [][]byte
 	for _, r := range redisRes {
-		j, err := newJobFromString(r)
+		if r == nil {
+			continue
+		}
+		j, err := q.decodeValue(r)
+		if err != nil {
+			mErr = multierror.Append(mErr, err)
+			// later jobs are popped meanwhile
+			var stub Job
+			if msgpack.Unmarshal(r, &stub) == nil {
+				stub.WhenUnixNano = now.Add(q.undecodableDelay).UnixNano()
+				if b, err := msgpack.Marshal(&stub); err == nil {
+					failed = append(failed, StoredJob{ID: stub.ID, When: stub.WhenUnixNano, Value: b})
+				}
+			}
+			continue
+		}
+		res, popped = append(res, j), append(popped, r)
+	}
+	if len(failed) > 0 {
+		if _, err := q.store.Push(failed...); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("can't push back undecodable jobs: %w", err))
+		}
+	}
+	// offloaded contents are deleted once restored
+	if err := q.dropBlobs(popped); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	return res, mErr
+}
+
+// Reserve returns multiple jobs from the queue, keeping them reserved until
+// acknowledged with Ack. Jobs neither acknowledged nor released with Nack before
+// the visibility timeout are queued again. Safe for concurrent use
+// (multiple goroutines must use their own Queue objects and redis connections)
+func (q *Queue) Reserve(limit int, visibility time.Duration) (res []*Job, err error) {
+	if limit <= 0 {
+		return res, ErrLimitZero
+	}
+	now := q.now()
+	redisRes, err := q.store.Reserve(now.UnixNano(), limit, now.Add(visibility).UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	return q.decode(redisRes)
+}
+
+// Ack acknowledges reserved jobs, removing them from the queue.
+func (q *Queue) Ack(ids ...string) error {
+	if len(ids) == 0 {
+		return ErrNoIDs
+	}
+	n, removed, err := q.store.Ack(ids...)
+	if err == nil && n != len(ids) {
+		err = fmt.Errorf("%w: can't ack all jobs %v in queue %s", ErrNotReserved, ids, q.Name)
+	}
+	if dErr := q.dropBlobs(removed); err == nil {
+		err = dErr
+	}
+	return err
+}
+
+// Nack releases reserved jobs, queuing them again to be processed after delay.
+func (q *Queue) Nack(delay time.Duration, ids ...string) error {
+	if len(ids) == 0 {
+		return ErrNoIDs
+	}
+	n, err := q.store.Nack(q.now().Add(delay).UnixNano(), ids...)
+	if err == nil && n != len(ids) {
+		err = fmt.Errorf("%w: can't nack all jobs %v in queue %s", ErrNotReserved, ids, q.Name)
+	}
+	return err
+}
+
+// Peek returns the next jobs of the queue, including scheduled jobs that are
+// not due yet, without removing them.
+func (q *Queue) Peek(limit int) ([]*Job, error) {
+	return q.List(0, limit)
+}
+
+// List returns pending jobs by execution time, skipping the first offset ones,
+// without removing them.
+func (q *Queue) List(offset, limit int) (res []*Job, err error) {
+	if limit <= 0 {
+		return res, ErrLimitZero
+	}
+	redisRes, err := q.store.List(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return q.decode(redisRes)
+}
+
+// Get returns a pending or reserved job by id, nil if not found.
+func (q *Queue) Get(id string) (*Job, error) {
+	b, err := q.store.Get(id)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return q.Decode(b)
+}
+
+// Requeue moves jobs of the queue, e.g. a dead-letter queue, to dst, to be
+// processed right away. Jobs are pushed to dst before being removed from the
+// queue, they are in both queues if the removal fails.
+func (q *Queue) Requeue(dst *Queue, ids ...string) error {
+	if len(ids) == 0 {
+		return ErrNoIDs
+	}
+	var jobs []*Job
+	for _, id := range ids {
+		j, err := q.Get(id)
+		if err != nil {
+			return err
+		}
+		if j == nil {
+			return fmt.Errorf("%w: job %s in queue %s", ErrNotFound, id, q.Name)
+		}
+		// internal fields (encryption key, blob) are set again by dst
+		jobs = append(jobs, &Job{
+			Content: j.Content,
+			Headers: j.Headers,
+			ID:      j.ID,
+			Payload: j.Payload,
+			Subject: j.Subject,
+			When:    dst.now(),
+		})
+	}
+	if _, err := dst.Push(jobs...); err != nil {
+		return err
+	}
+	return q.Remove(ids...)
+}
+
+// Reschedule changes the execution time of pending jobs.
+func (q *Queue) Reschedule(when time.Time, ids ...string) error {
+	if len(ids) == 0 {
+		return ErrNoIDs
+	}
+	for _, id := range ids {
+		if err := q.reschedule(id, when); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reschedule updates the score of a job and the execution time of its payload,
+// the update is retried if the payload changed in the meantime.
+func (q *Queue) reschedule(id string, when time.Time) error {
+	for {
+		old, err := q.store.Get(id)
+		if err != nil {
+			return err
+		}
+		if old == nil {
+			return fmt.Errorf("%w: job %s in queue %s", ErrNotFound, id, q.Name)
+		}
+		var j Job
+		if err := msgpack.Unmarshal(old, &j); err != nil {
+			return err
+		}
+		j.WhenUnixNano = when.UnixNano()
+		b, err := msgpack.Marshal(&j)
+		if err != nil {
+			return err
+		}
+		ok, err := q.store.Reschedule(id, old, b, j.WhenUnixNano)
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("%w: job %s is not pending in queue %s", ErrNotFound, id, q.Name)
+		}
+		if err != nil || ok {
+			return err
+		}
+	}
+}
+
+// Decode returns the job of value, as given to a Store, restoring its
+// offloaded contents.
+func (q *Queue) Decode(value []byte) (*Job, error) {
+	jobs, err := q.decode([][]byte{value})
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+// decode builds jobs from their redis values, restoring offloaded ones.
+func (q *Queue) decode(values [][]byte) (res []*Job, err error) {
+	var mErr error
+	for _, r := range values {
+		if r == nil {
+			continue
+		}
+		j, err := q.decodeValue(r)
 		if err != nil {
 			mErr = multierror.Append(mErr, err)
 			continue
 		}
 		res = append(res, j)
 	}
-	return res, nil
+	return res, mErr
+}
+
+// decodeValue builds a job from its redis value, restoring it if offloaded.
+func (q *Queue) decodeValue(value []byte) (*Job, error) {
+	j, err := newJobFromBytes(value, q.keys)
+	if err == nil && j.Blob != "" && q.blobs != nil {
+		j, err = q.restore(j)
+	}
+	return j, err
 }
 
 // Remove removes a job from the queue
 func (q *Queue) Remove(ids ...string) error {
 	if len(ids) == 0 {
-		return fmt.Errorf("no id provided")
+		return ErrNoIDs
 	}
-	c, managed := q.Conn()
-	if managed {
-		defer c.Close()
+	removed, err := q.store.Remove(ids...)
+	if err == nil && len(removed) != len(ids) {
+		err = fmt.Errorf("%w: can't delete all jobs %v in queue %s", ErrNotFound, ids, q.Name)
 	}
-	ok, err := redis.Int(removeScript.Do(c, redis.Args{q.Name}.AddFlat(ids)...))
-	if err == nil && ok != 1 {
-		err = fmt.Errorf("can't delete all jobs %v in queue %s", ids, q.Name)
+	if dErr := q.dropBlobs(removed); err == nil {
+		err = dErr
 	}
 	return err
 }
@@ -1,8 +1,10 @@
 package airq
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,8 +25,11 @@ func setup(t *testing.T) (*Queue, func()) {
 		if managed {
 			defer conn.Close()
 		}
-		conn.Send("DEL", q.Name)
-		conn.Send("DEL", q.Name+":values")
+		conn.Send("DEL", q.key())
+		conn.Send("DEL", q.valuesKey())
+		conn.Send("DEL", q.reservedKey())
+		conn.Send("SREM", q.registryKey(), q.Name)
+		conn.Flush()
 		conn.Close()
 	}
 	return q, teardown
@@ -228,7 +233,9 @@ func TestRemove(t *testing.T) {
 		Job{Content: "older", When: time.Now().Add(-200 * time.Millisecond), ID: "02"},
 	)
 
-	q.Remove("01", "02")
+	if err := q.Remove("01", "02"); err != nil {
+		t.Error(err)
+	}
 
 	jobs, err := q.PopJobs(3)
 	if err != nil {
@@ -241,3 +248,334 @@ func TestRemove(t *testing.T) {
 		t.Error("Expected to having jobs off the queue:", expected, " but I got this:", jobs)
 	}
 }
+
+func TestPayload(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	payload := []byte{0x00, 0xff, 0x10, 0x80, 0x00}
+	addJobs(t, q, Job{Payload: payload, Subject: "binary"})
+
+	job, err := q.Pop()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if !bytes.Equal(job.Payload, payload) {
+		t.Errorf("Expected payload %v, got %v", payload, job.Payload)
+	}
+	if job.Subject != "binary" {
+		t.Error("Expected subject to be kept, got", job.Subject)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q, Job{Content: "item", Headers: map[string]string{"trace-id": "abc", "tenant": "42"}})
+
+	job, err := q.Pop()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if job.Headers["trace-id"] != "abc" || job.Headers["tenant"] != "42" {
+		t.Error("Expected headers to be kept, got", job.Headers)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q,
+		Job{Content: "acked", When: time.Now().Add(-300 * time.Millisecond), ID: "01"},
+		Job{Content: "nacked", When: time.Now().Add(-200 * time.Millisecond), ID: "02"},
+		Job{Content: "expired", When: time.Now().Add(-100 * time.Millisecond), ID: "03"},
+	)
+
+	jobs, err := q.Reserve(2, time.Minute)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[0].ID != "01" || jobs[1].ID != "02" {
+		t.Error("Expected to reserve jobs 01 and 02, got", jobs)
+	}
+	expired, err := q.Reserve(1, 50*time.Millisecond)
+	if err != nil || len(expired) != 1 {
+		t.Error("Expected to reserve job 03, got", expired, err)
+	}
+	pending, _ := q.Pending()
+	if pending != 0 {
+		t.Error("Expected 0 job pending in queue, was", pending)
+	}
+
+	if err := q.Ack("01"); err != nil {
+		t.Error(err)
+	}
+	if err := q.Ack("01"); err == nil {
+		t.Error("Expected an error acking a job twice")
+	}
+	if j, _ := q.Get("01"); j != nil {
+		t.Error("Expected acked job to be removed, got", j)
+	}
+	if err := q.Nack(0, "02"); err != nil {
+		t.Error(err)
+	}
+
+	// Wait for the reservation of job 03 to expire.
+	time.Sleep(100 * time.Millisecond)
+
+	jobs, err = q.Reserve(3, time.Minute)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[0].Content != "nacked" || jobs[1].Content != "expired" {
+		t.Error("Expected to reserve nacked and expired jobs again, got", jobs)
+	}
+}
+
+func TestPeekAndGet(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	when := time.Now().Add(time.Hour)
+	addJobs(t, q,
+		Job{Content: "scheduled", When: when, ID: "01"},
+		Job{Content: "due", When: time.Now().Add(-100 * time.Millisecond), ID: "02"},
+	)
+
+	jobs, err := q.Peek(10)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[0].ID != "02" || jobs[1].ID != "01" {
+		t.Error("Expected to peek jobs 02 and 01, got", jobs)
+	}
+	pending, _ := q.Pending()
+	if pending != 2 {
+		t.Error("Expected 2 jobs pending in queue, was", pending)
+	}
+
+	job, err := q.Get("01")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if job == nil || job.Content != "scheduled" || !job.When.Equal(time.Unix(0, when.UnixNano())) {
+		t.Error("Expected to get job 01, got", job)
+	}
+	if job, err = q.Get("unknown"); job != nil || err != nil {
+		t.Error("Expected no job, got", job, err)
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q, Job{Content: "scheduled", When: time.Now().Add(time.Hour), ID: "01"})
+
+	if err := q.Reschedule(time.Now().Add(-time.Second), "01"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	job, err := q.Pop()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if job == nil || job.Content != "scheduled" || job.When.After(time.Now()) {
+		t.Error("Expected rescheduled job to be due, got", job)
+	}
+	if err := q.Reschedule(time.Now(), "01"); err == nil {
+		t.Error("Expected an error rescheduling a missing job")
+	}
+}
+
+func TestStats(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q,
+		Job{Content: "due 1", When: time.Now().Add(-200 * time.Millisecond)},
+		Job{Content: "due 2", When: time.Now().Add(-100 * time.Millisecond)},
+		Job{Content: "scheduled", When: time.Now().Add(time.Hour)},
+	)
+	if _, err := q.Reserve(1, time.Minute); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	stats, err := q.Stats()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if stats != (Stats{Pending: 2, Due: 1, Reserved: 1}) {
+		t.Error("Expected 2 jobs pending, 1 due and 1 reserved, got", stats)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q, Job{Content: "pending", ID: "01"})
+
+	if _, err := q.Push(); !errors.Is(err, ErrNoJobs) {
+		t.Error("Expected ErrNoJobs, got", err)
+	}
+	if _, err := q.PopJobs(0); !errors.Is(err, ErrLimitZero) {
+		t.Error("Expected ErrLimitZero, got", err)
+	}
+	if err := q.Ack(); !errors.Is(err, ErrNoIDs) {
+		t.Error("Expected ErrNoIDs, got", err)
+	}
+	if err := q.Ack("01"); !errors.Is(err, ErrNotReserved) {
+		t.Error("Expected ErrNotReserved, got", err)
+	}
+	if err := q.Remove("01", "02"); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if err := q.Reschedule(time.Now(), "01"); !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q, Job{Content: "reserved"}, Job{Content: "pending"})
+	if _, err := q.Reserve(1, time.Minute); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	n, err := q.Purge()
+	if err != nil {
+		t.Error(err)
+	}
+	if n != 2 {
+		t.Error("Expected 2 jobs purged, got", n)
+	}
+	if stats, _ := q.Stats(); stats != (Stats{}) {
+		t.Error("Expected an empty queue, got", stats)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	WithPrefix("test:")(q)
+
+	addJobs(t, q, Job{Content: "reserved"}, Job{Content: "popped"})
+	jobs, err := q.Reserve(1, time.Minute)
+	if err != nil || len(jobs) != 1 {
+		t.Error("Expected a reserved job, got", jobs, err)
+		t.FailNow()
+	}
+	for _, key := range []string{"test:{" + q.Name + "}", "test:{" + q.Name + "}:values", "test:{" + q.Name + "}:reserved"} {
+		if ok, _ := redis.Bool(q.conn.Do("EXISTS", key)); !ok {
+			t.Error("Expected key", key, "to exist")
+		}
+	}
+	if ok, _ := redis.Bool(q.conn.Do("EXISTS", q.Name)); ok {
+		t.Error("Expected no key", q.Name)
+	}
+	if err := q.Ack(jobs[0].ID); err != nil {
+		t.Error(err)
+	}
+	job, err := q.Pop()
+	if err != nil || job == nil || job.Content != "popped" {
+		t.Error("Expected popped, got", job, err)
+	}
+	infos, err := ListQueues(q.conn, WithPrefix("test:"))
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, info := range infos {
+		found = found || info.Name == q.Name
+	}
+	if !found {
+		t.Error("Expected", q.Name, "to be registered under the prefix, got", infos)
+	}
+}
+
+func TestList(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	addJobs(t, q,
+		Job{Content: "first", When: time.Now().Add(-300 * time.Millisecond)},
+		Job{Content: "second", When: time.Now().Add(-200 * time.Millisecond)},
+		Job{Content: "third", When: time.Now().Add(-100 * time.Millisecond)},
+	)
+	jobs, err := q.List(1, 5)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(jobs) != 2 || jobs[0].Content != "second" || jobs[1].Content != "third" {
+		t.Error("Expected the second and third jobs, got", jobs)
+	}
+}
+
+func TestListQueues(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	ns := randomName()
+	nsQueue := New(q.Name, WithConn(q.conn), WithNamespace(ns))
+	defer q.conn.Do("DEL", nsQueue.registryKey())
+
+	addJobs(t, q, Job{Content: "foo"}, Job{Content: "bar"})
+	if err := nsQueue.Register(); err != nil {
+		t.Error(err)
+	}
+
+	queues, err := ListQueues(q.conn)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	var found bool
+	for _, info := range queues {
+		if info.Name == q.Name {
+			found = info.Stats.Pending == 2
+		}
+	}
+	if !found {
+		t.Error("Expected queue to be listed with 2 jobs pending, got", queues)
+	}
+
+	queues, err = ListQueues(q.conn, WithNamespace(ns))
+	if err != nil {
+		t.Error(err)
+	}
+	if len(queues) != 1 || queues[0].Name != q.Name {
+		t.Error("Expected a single queue in namespace, got", queues)
+	}
+
+	if err := nsQueue.Unregister(); err != nil {
+		t.Error(err)
+	}
+	if names, err := ListQueueNames(q.conn, WithNamespace(ns)); err != nil || len(names) != 0 {
+		t.Error("Expected the queue to be unregistered, got", names, err)
+	}
+	// registered again on push
+	addJobs(t, nsQueue, Job{Content: "baz"})
+	if names, err := ListQueueNames(q.conn, WithNamespace(ns)); err != nil || len(names) != 1 {
+		t.Error("Expected the queue to be registered again, got", names, err)
+	}
+}
+
+func TestNoConnection(t *testing.T) {
+	if _, err := New(randomName()).Pending(); !errors.Is(err, ErrNoConnection) {
+		t.Error("Expected ErrNoConnection, got", err)
+	}
+}
@@ -0,0 +1,99 @@
+package airq
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// registryKey is the key of the set of queue names.
+const registryKey = "airq:queues"
+
+// QueueInfo describes a queue found by ListQueues.
+type QueueInfo struct {
+	Name  string `json:"name"`
+	Stats Stats  `json:"stats"`
+}
+
+// WithNamespace registers the queue in the named namespace, listed by
+// ListQueues with the same option.
+func WithNamespace(ns string) Option { return func(q *Queue) { q.namespace = ns } }
+
+func (q *Queue) registryKey() string {
+	if q.namespace == "" {
+		return q.prefix + registryKey
+	}
+	return q.prefix + registryKey + ":" + q.namespace
+}
+
+// Register adds the queue to the queues listed by ListQueues. Queues are
+// registered on their first push.
+func (q *Queue) Register() error {
+	if atomic.LoadUint32(&q.registered) == 1 {
+		return nil
+	}
+	// on a cluster, the registry is not in the slot of the queue
+	c, release := q.executorFor(q.registryKey())
+	defer release()
+	if _, err := c.Do("SADD", q.registryKey(), q.Name); err != nil {
+		return err
+	}
+	atomic.StoreUint32(&q.registered, 1)
+	return nil
+}
+
+// Unregister removes the queue from the queues listed by ListQueues, e.g.
+// once purged to be deleted. Jobs left in the queue are kept, and the queue
+// is registered again on the next push of this Queue; other Queue objects
+// having pushed already don't register it again.
+func (q *Queue) Unregister() error {
+	c, release := q.executorFor(q.registryKey())
+	defer release()
+	if _, err := c.Do("SREM", q.registryKey(), q.Name); err != nil {
+		return err
+	}
+	atomic.StoreUint32(&q.registered, 0)
+	return nil
+}
+
+// ListQueueNames returns the names of the registered queues, sorted. Options
+// are those of the queues, e.g. their namespace. c is not used, and may be
+// nil, if they give an Executor or a ConnProvider (e.g. WithCluster).
+func ListQueueNames(c redis.Conn, opts ...Option) ([]string, error) {
+	q := listed(c, "", opts)
+	rc, release := q.executorFor(q.registryKey())
+	defer release()
+	names, err := redis.Strings(rc.Do("SMEMBERS", q.registryKey()))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListQueues returns the registered queues, sorted by name, with their
+// counters. Options are those of the queues, e.g. their namespace. With a
+// ConnProvider, the counters of each queue are read from its node.
+func ListQueues(c redis.Conn, opts ...Option) ([]QueueInfo, error) {
+	names, err := ListQueueNames(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]QueueInfo, 0, len(names))
+	for _, name := range names {
+		stats, err := listed(c, name, opts).Stats()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, QueueInfo{Name: name, Stats: stats})
+	}
+	return res, nil
+}
+
+// listed returns the named queue using c, unless opts give an Executor or a ConnProvider.
+func listed(c redis.Conn, name string, opts []Option) *Queue {
+	q := New(name, opts...)
+	q.conn, q.Pool = c, nil
+	return q
+}
@@ -1,10 +1,7 @@
 package airq
 
-import "github.com/gomodule/redigo/redis"
-
-var popJobsScript = redis.NewScript(1, `
-local id_queue = KEYS[1]
-local content_queue = id_queue .. ":values"
+var popJobsScript = newScript(2, `
+local id_queue, content_queue = KEYS[1], KEYS[2]
 local timestamp = ARGV[1]
 local limit = ARGV[2]
 local keys = redis.call("zrangebyscore", id_queue, "-inf", timestamp, "LIMIT", 0, limit)
@@ -14,18 +11,103 @@ redis.call("zrem", id_queue, unpack(keys))
 redis.call("hdel", content_queue, unpack(keys))
 return values`)
 
-var pushScript = redis.NewScript(1, `
-local id_queue = KEYS[1]
-local content_queue = id_queue .. ":values"
+var pushScript = newScript(2, `
+local id_queue, content_queue = KEYS[1], KEYS[2]
+local replaced = {}
 for i=1, #ARGV do
 	local _, job = cmsgpack.unpack_one(ARGV[i])
+	local old = redis.call("hget", content_queue, job.id)
+	if old then table.insert(replaced, old) end
 	redis.call("zadd", id_queue, job.when, job.id)
 	redis.call("hset", content_queue, job.id, ARGV[i])
 end
-return 1`)
+return replaced`)
 
-var removeScript = redis.NewScript(1, `
-local id_queue = KEYS[1]
-local content_queue = id_queue .. ":values"
+var removeScript = newScript(3, `
+local id_queue, content_queue, reserved_queue = KEYS[1], KEYS[2], KEYS[3]
+local removed = {}
+for i=1, #ARGV do
+	local value = redis.call("hget", content_queue, ARGV[i])
+	if value then table.insert(removed, value) end
+end
 redis.call("zrem", id_queue, unpack(ARGV))
-return redis.call("hdel", content_queue, unpack(ARGV))`)
+redis.call("zrem", reserved_queue, unpack(ARGV))
+redis.call("hdel", content_queue, unpack(ARGV))
+return removed`)
+
+var reserveScript = newScript(3, `
+local id_queue, content_queue, reserved_queue = KEYS[1], KEYS[2], KEYS[3]
+local timestamp = ARGV[1]
+local limit = ARGV[2]
+local deadline = ARGV[3]
+local expired = redis.call("zrangebyscore", reserved_queue, "-inf", timestamp)
+for i=1, #expired do
+	redis.call("zadd", id_queue, timestamp, expired[i])
+end
+if table.getn(expired) > 0 then redis.call("zrem", reserved_queue, unpack(expired)) end
+local keys = redis.call("zrangebyscore", id_queue, "-inf", timestamp, "LIMIT", 0, limit)
+if table.getn(keys) == 0 then return {} end
+local values = redis.call("hmget", content_queue, unpack(keys))
+redis.call("zrem", id_queue, unpack(keys))
+for i=1, #keys do
+	redis.call("zadd", reserved_queue, deadline, keys[i])
+end
+return values`)
+
+var ackScript = newScript(3, `
+local id_queue, content_queue, reserved_queue = KEYS[1], KEYS[2], KEYS[3]
+local acked, removed = 0, {}
+for i=1, #ARGV do
+	if redis.call("zrem", reserved_queue, ARGV[i]) == 1 then
+		acked = acked + 1
+		if not redis.call("zscore", id_queue, ARGV[i]) then
+			local value = redis.call("hget", content_queue, ARGV[i])
+			if value then table.insert(removed, value) end
+			redis.call("hdel", content_queue, ARGV[i])
+		end
+	end
+end
+return {acked, removed}`)
+
+var nackScript = newScript(2, `
+local id_queue, reserved_queue = KEYS[1], KEYS[2]
+local timestamp = ARGV[1]
+local nacked = 0
+for i=2, #ARGV do
+	if redis.call("zrem", reserved_queue, ARGV[i]) == 1 then
+		nacked = nacked + 1
+		redis.call("zadd", id_queue, timestamp, ARGV[i])
+	end
+end
+return nacked`)
+
+var peekScript = newScript(2, `
+local id_queue, content_queue = KEYS[1], KEYS[2]
+local offset, limit = tonumber(ARGV[1]), tonumber(ARGV[2])
+local keys = redis.call("zrange", id_queue, offset, offset + limit - 1)
+if table.getn(keys) == 0 then return {} end
+return redis.call("hmget", content_queue, unpack(keys))`)
+
+var rescheduleScript = newScript(2, `
+local id_queue, content_queue = KEYS[1], KEYS[2]
+local id, old, new, timestamp = ARGV[1], ARGV[2], ARGV[3], ARGV[4]
+if not redis.call("zscore", id_queue, id) then return 0 end
+if redis.call("hget", content_queue, id) ~= old then return -1 end
+redis.call("hset", content_queue, id, new)
+redis.call("zadd", id_queue, timestamp, id)
+return 1`)
+
+var statsScript = newScript(2, `
+local id_queue, reserved_queue = KEYS[1], KEYS[2]
+local timestamp = ARGV[1]
+return {
+	redis.call("zcard", id_queue),
+	redis.call("zcount", id_queue, "-inf", timestamp),
+	redis.call("zcard", reserved_queue),
+}`)
+
+var purgeScript = newScript(3, `
+local id_queue, content_queue, reserved_queue = KEYS[1], KEYS[2], KEYS[3]
+local values = redis.call("hvals", content_queue)
+redis.call("del", id_queue, content_queue, reserved_queue)
+return values`)
@@ -0,0 +1,217 @@
+package airq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/hashicorp/go-multierror"
+)
+
+// WithSentinel connects the queue to the master monitored by the sentinels of
+// s, reconnecting after a failover.
+func WithSentinel(s *Sentinel) Option { return func(q *Queue) { q.provider = s } }
+
+// Sentinel is a ConnProvider for a master monitored by Redis Sentinel. The
+// master is discovered from the sentinels, and again when a connection fails
+// or the master became a replica, the pool being rebuilt if it changed.
+type Sentinel struct {
+	// Addrs are the addresses of the sentinels.
+	Addrs []string
+	// MasterName is the name of the master monitored by the sentinels.
+	MasterName string
+	// Dial connects to a sentinel or the master, redis.Dial("tcp", addr) if nil.
+	Dial func(addr string) (redis.Conn, error)
+	// MaxIdle, MaxActive and IdleTimeout configure the pool of the master,
+	// MaxIdle being 8 if 0.
+	MaxIdle     int
+	MaxActive   int
+	IdleTimeout time.Duration
+
+	mu    sync.Mutex
+	addr  string // of the master
+	pool  *redis.Pool
+	stale bool
+}
+
+// Get returns a connection to the master, key being ignored.
+func (s *Sentinel) Get(string) redis.Conn {
+	p, err := s.masterPool()
+	if err != nil {
+		return errorConn{err}
+	}
+	return sentinelConn{Conn: p.Get(), sentinel: s}
+}
+
+// MasterAddr returns the address of the master, discovered if unknown.
+func (s *Sentinel) MasterAddr() (string, error) {
+	if _, err := s.masterPool(); err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr, nil
+}
+
+// Close closes the pool of the master.
+func (s *Sentinel) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pool == nil {
+		return nil
+	}
+	err := s.pool.Close()
+	s.pool, s.addr = nil, ""
+	return err
+}
+
+// masterPool returns the pool of the master, rebuilt if a failover changed it.
+func (s *Sentinel) masterPool() (*redis.Pool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pool != nil && !s.stale {
+		return s.pool, nil
+	}
+	addr, err := s.discover()
+	if err != nil {
+		return nil, err
+	}
+	s.stale = false
+	if s.pool != nil && addr == s.addr {
+		return s.pool, nil
+	}
+	if s.pool != nil {
+		// active connections are closed when released
+		s.pool.Close()
+	}
+	s.addr, s.pool = addr, s.newPool(addr)
+	return s.pool, nil
+}
+
+// discover asks the sentinels for the master address, the first answering
+// being asked first next time.
+func (s *Sentinel) discover() (string, error) {
+	var mErr error
+	for i, addr := range s.Addrs {
+		master, err := s.masterOf(addr)
+		if err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+		s.Addrs[0], s.Addrs[i] = s.Addrs[i], s.Addrs[0]
+		return master, nil
+	}
+	if mErr == nil {
+		return "", fmt.Errorf("%w: no sentinel address", ErrUnavailable)
+	}
+	return "", fmt.Errorf("%w: master %s: %v", ErrUnavailable, s.MasterName, mErr)
+}
+
+func (s *Sentinel) masterOf(addr string) (string, error) {
+	c, err := s.dial(addr)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+	res, err := redis.Strings(c.Do("SENTINEL", "get-master-addr-by-name", s.MasterName))
+	if err == redis.ErrNil {
+		return "", fmt.Errorf("unknown master %s", s.MasterName)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(res) != 2 {
+		return "", fmt.Errorf("invalid master address %v", res)
+	}
+	return net.JoinHostPort(res[0], res[1]), nil
+}
+
+// failover rediscovers the master after err, reporting whether operations
+// can be retried.
+func (s *Sentinel) failover(err error) bool {
+	if !failoverError(err) {
+		return false
+	}
+	s.markStale()
+	_, err = s.masterPool()
+	return err == nil
+}
+
+func (s *Sentinel) markStale() {
+	s.mu.Lock()
+	s.stale = true
+	s.mu.Unlock()
+}
+
+func (s *Sentinel) dial(addr string) (redis.Conn, error) {
+	if s.Dial != nil {
+		return s.Dial(addr)
+	}
+	return redis.Dial("tcp", addr)
+}
+
+func (s *Sentinel) newPool(addr string) *redis.Pool {
+	maxIdle := s.MaxIdle
+	if maxIdle == 0 {
+		maxIdle = defaultIdle
+	}
+	return &redis.Pool{
+		MaxIdle:     maxIdle,
+		MaxActive:   s.MaxActive,
+		IdleTimeout: s.IdleTimeout,
+		Dial:        func() (redis.Conn, error) { return s.dial(addr) },
+	}
+}
+
+// sentinelConn marks the master stale when Do fails as after a failover.
+type sentinelConn struct {
+	redis.Conn
+	sentinel *Sentinel
+}
+
+func (c sentinelConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(cmd, args...)
+	if failoverError(err) {
+		c.sentinel.markStale()
+	}
+	return reply, err
+}
+
+// failoverError reports whether err is a lost connection or a write to a
+// master demoted to replica.
+func failoverError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var nErr net.Error
+	if errors.As(err, &nErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var rErr redis.Error
+	return errors.As(err, &rErr) && (strings.HasPrefix(string(rErr), "READONLY") || strings.HasPrefix(string(rErr), "LOADING"))
+}
+
+// failoverProvider is a ConnProvider reconnecting after a failover.
+type failoverProvider interface {
+	failover(err error) bool
+}
+
+// idempotent runs op with a connection of the queue, again with a new
+// connection if op failed because of a failover the provider recovered from.
+func (q *Queue) idempotent(op func(Executor) error) error {
+	run := func() error {
+		c, release := q.exec()
+		defer release()
+		return op(c)
+	}
+	err := run()
+	if f, ok := q.provider.(failoverProvider); ok && f.failover(err) {
+		err = run()
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package airq
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSentinel answers SENTINEL get-master-addr-by-name with master.
+type fakeSentinel struct {
+	net.Listener
+	sync.Mutex
+	master string
+}
+
+func newFakeSentinel(t *testing.T, master string) *fakeSentinel {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	s := &fakeSentinel{Listener: l, master: master}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSentinel) setMaster(addr string) {
+	s.Lock()
+	s.master = addr
+	s.Unlock()
+}
+
+func (s *fakeSentinel) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		// commands are arrays of bulk strings: *2\r\n$4\r\nPING\r\n...
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			if _, err := r.ReadString('\n'); err != nil {
+				return
+			}
+			arg, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			args[i] = strings.TrimSpace(arg)
+		}
+		s.Lock()
+		host, port, _ := net.SplitHostPort(s.master)
+		s.Unlock()
+		if len(args) == 3 && strings.EqualFold(args[0], "SENTINEL") && args[2] == "mymaster" {
+			fmt.Fprintf(c, "*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(host), host, len(port), port)
+		} else {
+			fmt.Fprint(c, "*-1\r\n")
+		}
+	}
+}
+
+// proxy forwards connections to addr until closed, as a master going down.
+type proxy struct {
+	net.Listener
+	sync.Mutex
+	conns []net.Conn
+}
+
+func newProxy(t *testing.T, addr string) *proxy {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	p := &proxy{Listener: l}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			backend, err := net.Dial("tcp", addr)
+			if err != nil {
+				c.Close()
+				continue
+			}
+			p.Lock()
+			p.conns = append(p.conns, c, backend)
+			p.Unlock()
+			go io.Copy(backend, c)
+			go io.Copy(c, backend)
+		}
+	}()
+	return p
+}
+
+func (p *proxy) Close() error {
+	p.Lock()
+	defer p.Unlock()
+	for _, c := range p.conns {
+		c.Close()
+	}
+	return p.Listener.Close()
+}
+
+func TestSentinel(t *testing.T) {
+	q, teardown := setup(t)
+	old := newProxy(t, "127.0.0.1:6379")
+	defer old.Close()
+	fs := newFakeSentinel(t, old.Addr().String())
+	defer fs.Close()
+	s := &Sentinel{Addrs: []string{"127.0.0.1:1", fs.Addr().String()}, MasterName: "mymaster"}
+	defer s.Close()
+	WithSentinel(s)(q)
+	defer teardown()
+
+	addJobs(t, q, Job{Content: "job"})
+	if addr, err := s.MasterAddr(); err != nil || addr != old.Addr().String() {
+		t.Error("Expected master", old.Addr(), "got", addr, err)
+	}
+
+	// failover to the real master
+	old.Close()
+	fs.setMaster("127.0.0.1:6379")
+	if pending, err := q.Pending(); err != nil || pending != 1 {
+		t.Error("Expected 1 job pending after failover, got", pending, err)
+	}
+	if addr, _ := s.MasterAddr(); addr != "127.0.0.1:6379" {
+		t.Error("Expected the new master, got", addr)
+	}
+	if jobs, err := q.Peek(1); err != nil || len(jobs) != 1 {
+		t.Error("Expected 1 job peeked, got", jobs, err)
+	}
+	if _, err := q.Push(&Job{Content: "other"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSentinelUnavailable(t *testing.T) {
+	q := New(randomName(), WithSentinel(&Sentinel{Addrs: []string{"127.0.0.1:1"}, MasterName: "mymaster"}))
+	if _, err := q.Pending(); !errors.Is(err, ErrUnavailable) {
+		t.Error("Expected ErrUnavailable, got", err)
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"path"
+	"strings"
+
+	"github.com/jney/airq/job"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authorizer checks whether a request is allowed to run operation (the RPC
+// name, e.g. "Push") on the named queue.
+type Authorizer interface {
+	Authorize(ctx context.Context, queue, operation string) error
+}
+
+// AuthorizerFunc is a function implementing Authorizer.
+type AuthorizerFunc func(ctx context.Context, queue, operation string) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, queue, operation string) error {
+	return f(ctx, queue, operation)
+}
+
+// Grant lists the queues and operations allowed to a token, any if empty.
+type Grant struct {
+	Queues     []string
+	Operations []string
+}
+
+func (g Grant) allows(queue, operation string) bool {
+	return (len(g.Queues) == 0 || contains(g.Queues, queue)) &&
+		(len(g.Operations) == 0 || contains(g.Operations, operation))
+}
+
+// TokenAuth authorizes requests carrying one of its bearer tokens in their
+// "authorization" metadata, within the token grant.
+type TokenAuth map[string]Grant
+
+func (a TokenAuth) Authorize(ctx context.Context, queue, operation string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	for _, v := range md.Get("authorization") {
+		if strings.HasPrefix(v, "Bearer ") {
+			token = strings.TrimPrefix(v, "Bearer ")
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	for t, g := range a {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) != 1 {
+			continue
+		}
+		if !g.allows(queue, operation) {
+			return status.Errorf(codes.PermissionDenied, "%s not allowed on queue %q", operation, queue)
+		}
+		return nil
+	}
+	return status.Error(codes.Unauthenticated, "invalid bearer token")
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// queueName returns the queue name of a request, defaultQueue if not set.
+func queueName(req interface{}, defaultQueue string) string {
+	var name string
+	switch r := req.(type) {
+	case interface{ GetQueue() string }:
+		name = r.GetQueue()
+	case *job.Queue:
+		name = r.GetName()
+	}
+	if name == "" {
+		return defaultQueue
+	}
+	return name
+}
+
+// jobsService prefixes the methods of the Jobs service, the only one authorized,
+// so that other services registered on the server (e.g. health checks) stay open.
+const jobsService = "/job.Jobs/"
+
+func unaryAuth(a Authorizer, defaultQueue string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, jobsService) {
+			return handler(ctx, req)
+		}
+		if err := a.Authorize(ctx, queueName(req, defaultQueue), path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(a Authorizer, defaultQueue string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, jobsService) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authStream{
+			ServerStream: ss,
+			authorize: func(req interface{}) error {
+				return a.Authorize(ss.Context(), queueName(req, defaultQueue), path.Base(info.FullMethod))
+			},
+		})
+	}
+}
+
+// authStream authorizes the messages received on a stream.
+type authStream struct {
+	grpc.ServerStream
+	authorize func(interface{}) error
+}
+
+func (s *authStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authorize(m)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log"
+	"time"
+
+	"github.com/jney/airq/internal/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Option configures a Server.
+type Option func(*config)
+
+type config struct {
+	auth               Authorizer
+	cert               *tls.Certificate
+	clientCAs          *x509.CertPool
+	drainTimeout       time.Duration
+	errorHandler       func(queue string, err error)
+	limits             limits
+	maxQueues          int
+	serverOptions      []grpc.ServerOption
+	streamInterceptors []grpc.StreamServerInterceptor
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+}
+
+// WithTLS serves over TLS with the given certificate.
+func WithTLS(cert tls.Certificate) Option { return func(c *config) { c.cert = &cert } }
+
+// WithClientCAs requires clients to present a certificate signed by one of
+// pool (mutual TLS), it must be used along WithTLS.
+func WithClientCAs(pool *x509.CertPool) Option { return func(c *config) { c.clientCAs = pool } }
+
+// WithAuth checks every request with a.
+func WithAuth(a Authorizer) Option { return func(c *config) { c.auth = a } }
+
+// WithUnaryInterceptor adds interceptors to unary requests, run after authorization.
+func WithUnaryInterceptor(i ...grpc.UnaryServerInterceptor) Option {
+	return func(c *config) { c.unaryInterceptors = append(c.unaryInterceptors, i...) }
+}
+
+// WithStreamInterceptor adds interceptors to streaming requests, run after authorization.
+func WithStreamInterceptor(i ...grpc.StreamServerInterceptor) Option {
+	return func(c *config) { c.streamInterceptors = append(c.streamInterceptors, i...) }
+}
+
+// WithServerOptions passes options (keepalive, max message size, ...) to the grpc server.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(c *config) { c.serverOptions = append(c.serverOptions, opts...) }
+}
+
+// WithDrainTimeout bounds the time GracefulStop waits for pending requests,
+// remaining ones being canceled. GracefulStop waits for all of them if 0.
+func WithDrainTimeout(d time.Duration) Option { return func(c *config) { c.drainTimeout = d } }
+
+// WithErrorHandler reports with f the errors of the subscriptions polling
+// queues, which keep polling after them, log.Printf by default.
+func WithErrorHandler(f func(queue string, err error)) Option {
+	return func(c *config) { c.errorHandler = f }
+}
+
+// WithMaxBatchSize bounds the count of jobs pushed at once, DefaultMaxBatchSize
+// by default, unlimited if 0.
+func WithMaxBatchSize(n int) Option { return func(c *config) { c.limits.MaxBatchSize = n } }
+
+// WithMaxJobSize bounds the size of the content and payload of pushed jobs,
+// pushes above returning airq.ErrPayloadTooLarge.
+func WithMaxJobSize(n int) Option { return func(c *config) { c.limits.MaxJobSize = n } }
+
+// WithMaxQueues bounds the count of queues a multi-queue server keeps,
+// DefaultMaxQueues by default, the least recently used ones being dropped,
+// unlimited if 0.
+func WithMaxQueues(n int) Option { return func(c *config) { c.maxQueues = n } }
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		errorHandler: func(queue string, err error) { log.Printf("airq: subscription to %s: %v", queue, err) },
+		limits:       limits{MaxBatchSize: DefaultMaxBatchSize},
+		maxQueues:    DefaultMaxQueues,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// grpcServer builds the grpc server, defaultQueue being the queue name
+// used to authorize requests without queue name.
+func (c *config) grpcServer(defaultQueue string) *grpc.Server {
+	var opts []grpc.ServerOption
+	if c.cert != nil {
+		tc := &tls.Config{Certificates: []tls.Certificate{*c.cert}, MinVersion: tls.VersionTLS12}
+		if c.clientCAs != nil {
+			tc.ClientCAs = c.clientCAs
+			tc.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tc)))
+	}
+	// queue errors are converted to status errors before any other interceptor sees them
+	unary := append([]grpc.UnaryServerInterceptor{unaryStatus}, c.unaryInterceptors...)
+	stream := append([]grpc.StreamServerInterceptor{streamStatus}, c.streamInterceptors...)
+	if c.auth != nil {
+		unary = append([]grpc.UnaryServerInterceptor{unaryAuth(c.auth, defaultQueue)}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{streamAuth(c.auth, defaultQueue)}, stream...)
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	return grpc.NewServer(append(opts, c.serverOptions...)...)
+}
+
+func unaryStatus(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	return res, rpcerr.ToStatus(err)
+}
+
+func streamStatus(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return rpcerr.ToStatus(handler(srv, ss))
+}
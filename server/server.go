@@ -1,49 +1,149 @@
 package server
 
 import (
+	"container/list"
 	"context"
+	"fmt"
+	"io"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/gomodule/redigo/redis"
 	"github.com/jney/airq"
+	"github.com/jney/airq/internal/convert"
 	"github.com/jney/airq/job"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
 	*grpc.Server
 	job.JobsServer
 	*airq.Queue
+
+	drainTimeout time.Duration
+	errorHandler func(queue string, err error)
+	limits       limits
+	registry     *registry
+	stop         *stopper
+}
+
+// stopper notifies subscriptions the server is stopping.
+type stopper struct {
+	once sync.Once
+	done chan struct{}
 }
 
-func New(q *airq.Queue) Server {
-	return Server{
-		Server: grpc.NewServer(),
-		Queue:  q,
+// New defines a server for a single queue.
+func New(q *airq.Queue, opts ...Option) Server {
+	c := newConfig(opts)
+	s := Server{
+		Server:       c.grpcServer(q.Name),
+		Queue:        q,
+		drainTimeout: c.drainTimeout,
+		errorHandler: c.errorHandler,
+		limits:       c.limits,
+		stop:         &stopper{done: make(chan struct{})},
 	}
+	job.RegisterJobsServer(s.Server, s)
+	return s
 }
 
-func (s Server) Serve(connStr string) error {
+// NewMulti defines a server for any queue of pool, routing requests by queue
+// name. Queues are created on demand with queueOpts, only the allowed ones if not empty,
+// and at most WithMaxQueues of them are kept.
+// pool may be nil if queueOpts give a connection provider, e.g. airq.WithCluster.
+func NewMulti(pool *redis.Pool, allowed []string, queueOpts []airq.Option, opts ...Option) Server {
+	c := newConfig(opts)
+	r := &registry{
+		max:    c.maxQueues,
+		pool:   pool,
+		opts:   queueOpts,
+		queues: make(map[string]*list.Element),
+		used:   list.New(),
+	}
+	if len(allowed) > 0 {
+		r.allowed = make(map[string]bool, len(allowed))
+		for _, name := range allowed {
+			r.allowed[name] = true
+		}
+	}
+	s := Server{
+		Server:       c.grpcServer(""),
+		drainTimeout: c.drainTimeout,
+		errorHandler: c.errorHandler,
+		limits:       c.limits,
+		registry:     r,
+		stop:         &stopper{done: make(chan struct{})},
+	}
 	job.RegisterJobsServer(s.Server, s)
-	l, err := net.Listen("tcp", connStr)
+	return s
+}
+
+// Serve listens on connStr, a TCP address or a unix socket path prefixed by "unix:".
+func (s Server) Serve(connStr string) error {
+	network := "tcp"
+	if strings.HasPrefix(connStr, "unix:") {
+		network, connStr = "unix", strings.TrimPrefix(connStr, "unix:")
+	}
+	l, err := net.Listen(network, connStr)
 	if err != nil {
 		return err
 	}
+	return s.ServeListener(l)
+}
+
+// ServeListener serves requests on an existing listener, and can be called
+// for several listeners, e.g. TCP and a unix socket.
+func (s Server) ServeListener(l net.Listener) error {
 	return s.Server.Serve(l)
 }
 
+// GracefulStop stops accepting requests, ends subscriptions and waits for
+// pending requests, at most for the drain timeout if set.
+func (s Server) GracefulStop() {
+	s.stop.once.Do(func() { close(s.stop.done) })
+	if s.drainTimeout == 0 {
+		s.Server.GracefulStop()
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		s.Server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.drainTimeout):
+		s.Server.Stop()
+	}
+}
+
+// queue returns the queue a request is routed to.
+func (s Server) queue(name string) (*airq.Queue, error) {
+	if s.registry != nil {
+		return s.registry.get(name)
+	}
+	if name != "" && name != s.Queue.Name {
+		return nil, status.Errorf(codes.NotFound, "unknown queue %q", name)
+	}
+	return s.Queue, nil
+}
+
 func (s Server) Push(ctx context.Context, jobList *job.JobList) (*job.IdList, error) {
-	var jobs []*airq.Job
+	q, err := s.queue(jobList.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	jobs := convert.FromList(jobList)
+	if err := validateJobs(s.limits, jobs); err != nil {
+		return nil, err
+	}
 	idList := new(job.IdList)
-	for _, j := range jobList.Jobs {
-		jobs = append(jobs, &airq.Job{
-			ID:       j.GetId(),
-			Content:  j.GetContent(),
-			Strategy: airq.Strategy(j.GetStrategy()),
-			When:     time.Unix(0, j.GetWhen()),
-		})
-	}
-	ids, err := s.Queue.Push(jobs...)
+	ids, err := q.Push(jobs...)
 	if err != nil || len(ids) == 0 {
 		return idList, err
 	}
@@ -54,9 +154,224 @@ func (s Server) Push(ctx context.Context, jobList *job.JobList) (*job.IdList, er
 }
 
 func (s Server) Remove(ctx context.Context, jobs *job.IdList) (*job.Void, error) {
-	var ids []string
-	for _, i := range jobs.GetIds() {
-		ids = append(ids, i.Id)
+	q, err := s.queue(jobs.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	return &job.Void{}, q.Remove(convert.IDs(jobs.GetIds())...)
+}
+
+func (s Server) Pop(ctx context.Context, req *job.PopRequest) (*job.JobList, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*airq.Job
+	if req.GetVisibility() > 0 {
+		jobs, err = q.Reserve(int(req.GetLimit()), time.Duration(req.GetVisibility()))
+	} else {
+		jobs, err = q.PopJobs(int(req.GetLimit()))
+	}
+	// popped jobs are lost if not returned, the error is only reported if none could be decoded
+	if len(jobs) > 0 {
+		return convert.ToList(jobs), nil
+	}
+	return convert.ToList(jobs), err
+}
+
+func (s Server) Pending(ctx context.Context, req *job.Queue) (*job.Count, error) {
+	q, err := s.queue(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	n, err := q.Pending()
+	return &job.Count{Count: n}, err
+}
+
+func (s Server) Peek(ctx context.Context, req *job.PeekRequest) (*job.JobList, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := q.Peek(int(req.GetLimit()))
+	return convert.ToList(jobs), err
+}
+
+func (s Server) Get(ctx context.Context, req *job.GetRequest) (*job.Job, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	j, err := q.Get(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if j == nil {
+		return nil, fmt.Errorf("%w: job %s in queue %s", airq.ErrNotFound, req.GetId(), q.Name)
+	}
+	return convert.ToProto(j), nil
+}
+
+func (s Server) Ack(ctx context.Context, ids *job.IdList) (*job.Void, error) {
+	q, err := s.queue(ids.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	return &job.Void{}, q.Ack(convert.IDs(ids.GetIds())...)
+}
+
+func (s Server) Nack(ctx context.Context, req *job.NackRequest) (*job.Void, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	return &job.Void{}, q.Nack(time.Duration(req.GetDelay()), convert.IDs(req.GetIds())...)
+}
+
+func (s Server) Reschedule(ctx context.Context, req *job.RescheduleRequest) (*job.Void, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	return &job.Void{}, q.Reschedule(time.Unix(0, req.GetWhen()), convert.IDs(req.GetIds())...)
+}
+
+func (s Server) Stats(ctx context.Context, req *job.Queue) (*job.QueueStats, error) {
+	q, err := s.queue(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := q.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &job.QueueStats{Pending: stats.Pending, Due: stats.Due, Reserved: stats.Reserved}, nil
+}
+
+func (s Server) Purge(ctx context.Context, req *job.Queue) (*job.Count, error) {
+	q, err := s.queue(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	n, err := q.Purge()
+	return &job.Count{Count: n}, err
+}
+
+// Subscribe streams due jobs to the client until it disconnects. The next
+// jobs are only popped or reserved once the client asks for them, after
+// processing the previous ones, so that they don't wait in stream buffers.
+// Errors polling the queue are reported to the error handler (see
+// WithErrorHandler), the subscription polling again after sleep.
+func (s Server) Subscribe(stream job.Jobs_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return err
+	}
+	size, sleep := int(req.GetSize()), time.Duration(req.GetSleep())
+	if size <= 0 {
+		size = 100
+	}
+	if sleep <= 0 {
+		sleep = 3 * time.Second
+	}
+	ctx := stream.Context()
+	next, closed := make(chan struct{}), make(chan error, 1)
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				closed <- err
+				return
+			}
+			select {
+			case next <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for ctx.Err() == nil && !s.stopping() {
+		var jobs []*airq.Job
+		if req.GetVisibility() > 0 {
+			jobs, err = q.Reserve(size, time.Duration(req.GetVisibility()))
+		} else {
+			jobs, err = q.PopJobs(size)
+		}
+		if err != nil {
+			s.errorHandler(q.Name, err)
+		}
+		if len(jobs) > 0 {
+			if err := stream.Send(convert.ToList(jobs)); err != nil {
+				return err
+			}
+			// wait for the client to ask for the next jobs, the stream
+			// being closed when it's done
+			select {
+			case <-next:
+			case err := <-closed:
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			case <-ctx.Done():
+			case <-s.stop.done:
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.stop.done:
+		case <-time.After(sleep):
+		}
+	}
+	return nil
+}
+
+func (s Server) stopping() bool {
+	select {
+	case <-s.stop.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// registry holds the queues of a multi-queue server, dropping the least
+// recently used ones above max so that clients can't grow it without bounds.
+type registry struct {
+	sync.Mutex
+	allowed map[string]bool
+	max     int
+	opts    []airq.Option
+	pool    *redis.Pool
+	queues  map[string]*list.Element // of used
+	used    *list.List               // of *airq.Queue, most recently used first
+}
+
+func (r *registry) get(name string) (*airq.Queue, error) {
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "no queue name provided")
+	}
+	if r.allowed != nil && !r.allowed[name] {
+		return nil, status.Errorf(codes.PermissionDenied, "queue %q not allowed", name)
+	}
+	r.Lock()
+	defer r.Unlock()
+	if e, ok := r.queues[name]; ok {
+		r.used.MoveToFront(e)
+		return e.Value.(*airq.Queue), nil
+	}
+	q := airq.New(name, append([]airq.Option{airq.WithPool(r.pool)}, r.opts...)...)
+	r.queues[name] = r.used.PushFront(q)
+	if r.max > 0 && r.used.Len() > r.max {
+		last := r.used.Remove(r.used.Back()).(*airq.Queue)
+		delete(r.queues, last.Name)
 	}
-	return &job.Void{}, s.Queue.Remove(ids...)
+	return q, nil
 }
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/internal/validate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxBatchSize is the maximum count of jobs pushed at once, unless set with WithMaxBatchSize.
+	DefaultMaxBatchSize = validate.DefaultMaxBatchSize
+	// DefaultMaxQueues is the maximum count of queues kept by a multi-queue
+	// server, unless set with WithMaxQueues.
+	DefaultMaxQueues = 1000
+	// MaxIDLength is the maximum length of job ids set by clients.
+	MaxIDLength = validate.MaxIDLength
+)
+
+// limits bounds the requests accepted by a server.
+type limits = validate.Limits
+
+// validateJobs checks the jobs of a push request.
+func validateJobs(l limits, jobs []*airq.Job) error {
+	err := l.Jobs(jobs)
+	if errors.Is(err, validate.ErrInvalid) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -2,16 +2,29 @@ package airq_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"errors"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/jney/airq"
 	"github.com/jney/airq/client"
+	"github.com/jney/airq/internal/convert"
 	"github.com/jney/airq/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func newPool() *redis.Pool {
@@ -42,22 +55,45 @@ func setup(t *testing.T) (*airq.Queue, func()) {
 		conn := q.Pool.Get()
 		conn.Send("DEL", q.Name)
 		conn.Send("DEL", q.Name+":values")
+		conn.Send("DEL", q.Name+":reserved")
+		conn.Send("SREM", "airq:queues", q.Name)
 		conn.Close()
 	}
 	return q, teardown
 }
 
+// serve serves srv on a free local port, returning its address. Clients can
+// connect right away, the listener being open.
+func serve(t *testing.T, srv server.Server) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	go srv.ServeListener(l)
+	return l.Addr().String()
+}
+
+// freeAddr returns the address of a free local port, to be served later.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
 func TestService(t *testing.T) {
 	q, teardown := setup(t)
 	defer teardown()
 
-	connStr := ":42039"
-
 	srv := server.New(q)
-	go srv.Serve(connStr)
+	connStr := serve(t, srv)
 	defer srv.Stop()
-	// wait for the grpc server to be up
-	time.Sleep(200 * time.Millisecond)
 
 	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
 	if err != nil {
@@ -67,7 +103,7 @@ func TestService(t *testing.T) {
 	cli := client.New(conn)
 	idList, err := cli.Push(context.Background(),
 		&airq.Job{ID: "01", Content: "foo", When: time.Unix(0, 1)},
-		&airq.Job{ID: "02", Content: "bar", When: time.Unix(0, 2)},
+		&airq.Job{ID: "02", Content: "bar", When: time.Unix(0, 2), Headers: map[string]string{"trace-id": "abc"}, Subject: "email"},
 	)
 	if err != nil {
 		t.Error(err)
@@ -78,4 +114,622 @@ func TestService(t *testing.T) {
 	if err := cli.Remove(context.Background(), "01"); err != nil {
 		t.Error(err)
 	}
+	j, err := q.Pop()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if j.ID != "02" || j.Headers["trace-id"] != "abc" || j.Subject != "email" {
+		t.Error("Expected job 02 with its headers and subject, got", j)
+	}
+}
+
+// TestServiceJobFields checks every field set by users goes through the gRPC messages.
+func TestServiceJobFields(t *testing.T) {
+	internal := map[string]bool{
+		"Blob": true, "CompressedContent": true, "CompressedPayload": true, "Key": true, "WhenUnixNano": true,
+	}
+	var j airq.Job
+	v := reflect.ValueOf(&j).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if internal[v.Type().Field(i).Name] {
+			continue
+		}
+		switch f.Interface().(type) {
+		case string:
+			f.SetString(v.Type().Field(i).Name)
+		case []byte:
+			f.SetBytes([]byte(v.Type().Field(i).Name))
+		case map[string]string:
+			f.Set(reflect.ValueOf(map[string]string{"key": "value"}))
+		case time.Time:
+			f.Set(reflect.ValueOf(time.Unix(0, 42)))
+		case airq.Strategy:
+			f.Set(reflect.ValueOf(airq.CreateStrategy))
+		default:
+			t.Errorf("Unexpected field %s of type %s, to be converted or marked internal", v.Type().Field(i).Name, f.Type())
+		}
+	}
+	if got := convert.FromProto(convert.ToProto(&j)); !reflect.DeepEqual(got, &j) {
+		t.Errorf("Expected %+v after conversion, got %+v", j, got)
+	}
+}
+
+func TestServiceConsume(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q)
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ctx := context.Background()
+	cli := client.New(conn)
+	if _, err := cli.Push(ctx,
+		&airq.Job{ID: "01", Content: "foo", When: time.Unix(0, 1)},
+		&airq.Job{ID: "02", Content: "bar", When: time.Unix(0, 2)},
+		&airq.Job{ID: "03", Content: "baz", When: time.Now().Add(time.Hour)},
+	); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if n, err := cli.Pending(ctx); err != nil || n != 3 {
+		t.Error("Expected 3 jobs pending, got", n, err)
+	}
+	jobs, err := cli.Peek(ctx, 3)
+	if err != nil || len(jobs) != 3 || jobs[0].ID != "01" {
+		t.Error("Expected to peek 3 jobs, got", jobs, err)
+	}
+	if j, err := cli.Get(ctx, "03"); err != nil || j.Content != "baz" {
+		t.Error("Expected to get job 03, got", j, err)
+	}
+	if _, err := cli.Get(ctx, "unknown"); err == nil {
+		t.Error("Expected an error getting an unknown job")
+	}
+
+	jobs, err = cli.Reserve(ctx, 2, time.Minute)
+	if err != nil || len(jobs) != 2 || jobs[0].Content != "foo" || jobs[1].Content != "bar" {
+		t.Error("Expected to reserve jobs 01 and 02, got", jobs, err)
+	}
+	if err := cli.Ack(ctx, "01"); err != nil {
+		t.Error(err)
+	}
+	if err := cli.Nack(ctx, 0, "02"); err != nil {
+		t.Error(err)
+	}
+	if err := cli.Reschedule(ctx, time.Unix(0, 3), "03"); err != nil {
+		t.Error(err)
+	}
+
+	jobs, err = cli.Pop(ctx, 3)
+	if err != nil || len(jobs) != 2 || jobs[0].ID != "03" || jobs[1].ID != "02" {
+		t.Error("Expected to pop jobs 03 and 02, got", jobs, err)
+	}
+}
+
+func TestServiceSubscribe(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q)
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	cli := client.New(conn)
+	if _, err := cli.Push(context.Background(),
+		&airq.Job{ID: "01", Content: "foo"},
+		&airq.Job{ID: "02", Content: "fail once"},
+	); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var processed []string
+	failed := false
+	opts := &client.ConsumeOptions{Sleep: 10 * time.Millisecond, Visibility: time.Minute}
+	err = cli.Consume(ctx, opts, func(j *airq.Job) error {
+		if j.Content == "fail once" && !failed {
+			failed = true
+			return errors.New("failed")
+		}
+		processed = append(processed, j.ID)
+		if len(processed) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Error("Expected consume to be canceled, got", err)
+	}
+	if len(processed) != 2 || processed[0] != "01" || processed[1] != "02" {
+		t.Error("Expected jobs 01 and 02 to be processed, got", processed)
+	}
+	if n, _ := q.Pending(); n != 0 {
+		t.Error("Expected no job pending, got", n)
+	}
+}
+
+func TestServiceSubscribeErrors(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	keys, err := airq.NewKeyRing("k1", map[string][]byte{"k1": make([]byte, 32)})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	encrypted := airq.New(q.Name, airq.WithPool(q.Pool), airq.WithEncryption(keys))
+	if _, err := encrypted.Push(&airq.Job{Content: "secret", When: time.Now().Add(-time.Hour)}); err != nil {
+		t.Error(err)
+	}
+	if _, err := q.Push(&airq.Job{ID: "01", Content: "plain", When: time.Now().Add(-time.Minute)}); err != nil {
+		t.Error(err)
+	}
+
+	// the server has no key, the subscription goes on after the error
+	errs := make(chan error, 10)
+	srv := server.New(q, server.WithErrorHandler(func(queue string, err error) { errs <- err }))
+	connStr := serve(t, srv)
+	defer srv.Stop()
+	cli, err := client.Dial(connStr)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var processed []string
+	err = cli.Consume(ctx, &client.ConsumeOptions{Size: 1, Sleep: 10 * time.Millisecond}, func(j *airq.Job) error {
+		processed = append(processed, j.ID)
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Error("Expected consume to be canceled, got", err)
+	}
+	if len(processed) != 1 || processed[0] != "01" {
+		t.Error("Expected job 01 to be processed, got", processed)
+	}
+	if len(errs) == 0 {
+		t.Error("Expected the error to be reported")
+	}
+}
+
+func TestServiceSubscribeFlowControl(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q)
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	cli := client.New(conn)
+	if _, err := cli.Push(context.Background(),
+		&airq.Job{ID: "01", Content: "foo"},
+		&airq.Job{ID: "02", Content: "bar"},
+		&airq.Job{ID: "03", Content: "baz"},
+	); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var processed int
+	opts := &client.ConsumeOptions{Size: 1, Sleep: 10 * time.Millisecond, Visibility: time.Minute}
+	cli.Consume(ctx, opts, func(j *airq.Job) error {
+		// the next jobs are only reserved once this one is processed
+		time.Sleep(50 * time.Millisecond)
+		if stats, _ := q.Stats(); stats.Reserved != 1 {
+			t.Error("Expected a single job reserved while processing", j.ID, "got", stats)
+		}
+		if processed++; processed == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if processed != 3 {
+		t.Error("Expected 3 jobs processed, got", processed)
+	}
+}
+
+func TestServiceMultiQueue(t *testing.T) {
+	q1, teardown1 := setup(t)
+	defer teardown1()
+	q2 := airq.New(randomName(), airq.WithPool(q1.Pool))
+	defer func() {
+		conn := q2.Pool.Get()
+		conn.Do("DEL", q2.Name, q2.Name+":values")
+		conn.Do("SREM", "airq:queues", q2.Name)
+		conn.Close()
+	}()
+
+	// a single queue kept, the other one being created again when used
+	srv := server.NewMulti(q1.Pool, []string{q1.Name, q2.Name}, nil, server.WithMaxQueues(1))
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ctx := context.Background()
+	cli := client.New(conn)
+	if _, err := cli.WithQueue(q1.Name).Push(ctx, &airq.Job{Content: "foo"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := cli.WithQueue(q2.Name).Push(ctx, &airq.Job{Content: "bar"}, &airq.Job{Content: "baz"}); err != nil {
+		t.Error(err)
+	}
+	if n, _ := q1.Pending(); n != 1 {
+		t.Error("Expected 1 job pending in first queue, got", n)
+	}
+	if n, err := cli.WithQueue(q2.Name).Pending(ctx); err != nil || n != 2 {
+		t.Error("Expected 2 jobs pending in second queue, got", n, err)
+	}
+	if n, err := cli.WithQueue(q1.Name).Pending(ctx); err != nil || n != 1 {
+		t.Error("Expected 1 job pending in first queue, got", n, err)
+	}
+
+	if _, err := cli.Push(ctx, &airq.Job{Content: "foo"}); err == nil {
+		t.Error("Expected an error without queue name")
+	}
+	if _, err := cli.WithQueue(randomName()).Push(ctx, &airq.Job{Content: "foo"}); err == nil {
+		t.Error("Expected an error for a queue not allowed")
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q)
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ctx := context.Background()
+	cli := client.New(conn)
+
+	_, err = cli.Get(ctx, "missing")
+	if !errors.Is(err, airq.ErrNotFound) || status.Code(err) != codes.NotFound {
+		t.Error("Expected a not found error, got", err)
+	}
+	_, err = cli.Peek(ctx, 0)
+	if !errors.Is(err, airq.ErrLimitZero) || status.Code(err) != codes.InvalidArgument {
+		t.Error("Expected an invalid argument error, got", err)
+	}
+	ids, err := cli.Push(ctx, &airq.Job{Content: "foo"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	err = cli.Ack(ctx, ids.Ids[0].Id)
+	if !errors.Is(err, airq.ErrNotReserved) || status.Code(err) != codes.FailedPrecondition {
+		t.Error("Expected a failed precondition error, got", err)
+	}
+
+	if stats, err := cli.Stats(ctx); err != nil || stats.Pending != 1 {
+		t.Error("Expected 1 job pending, got", stats, err)
+	}
+	if n, err := cli.Purge(ctx); err != nil || n != 1 {
+		t.Error("Expected 1 job purged, got", n, err)
+	}
+
+	srv.Stop()
+	_, err = cli.Pending(ctx)
+	if !errors.Is(err, airq.ErrUnavailable) || status.Code(err) != codes.Unavailable {
+		t.Error("Expected an unavailable error, got", err)
+	}
+}
+
+func TestServiceValidation(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q, server.WithMaxBatchSize(2), server.WithMaxJobSize(8))
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ctx := context.Background()
+	cli := client.New(conn)
+
+	before := time.Now()
+	ids, err := cli.Push(ctx, &airq.Job{Content: "now"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	j, err := cli.Get(ctx, ids.Ids[0].Id)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if j.When.Before(before) || j.When.After(time.Now()) {
+		t.Error("Expected a job without execution time to be scheduled now, got", j.When)
+	}
+
+	for _, tc := range []struct {
+		name string
+		jobs []*airq.Job
+	}{
+		{"unknown strategy", []*airq.Job{{Content: "foo", Strategy: 42}}},
+		{"keep strategy", []*airq.Job{{Content: "foo", Strategy: airq.KeepStrategy}}},
+		{"invalid id", []*airq.Job{{Content: "foo", ID: "foo bar"}}},
+		{"batch too large", []*airq.Job{{Content: "1"}, {Content: "2"}, {Content: "3"}}},
+	} {
+		if _, err := cli.Push(ctx, tc.jobs...); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected an invalid argument error for %s, got %v", tc.name, err)
+		}
+	}
+	if _, err := cli.Push(ctx, &airq.Job{Content: "too large"}); !errors.Is(err, airq.ErrPayloadTooLarge) {
+		t.Error("Expected ErrPayloadTooLarge, got", err)
+	}
+	if n, _ := q.Pending(); n != 1 {
+		t.Error("Expected invalid jobs not to be pushed, got", n, "jobs pending")
+	}
+}
+
+func TestServiceProducer(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	// the server starts after the first batch has been sent, to be retried
+	srv := server.New(q)
+	defer srv.Stop()
+	connStr := freeAddr(t)
+
+	conn, err := grpc.Dial(connStr, grpc.WithInsecure())
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	var failed int
+	p := client.NewProducer(client.New(conn), &client.ProducerOptions{
+		BatchSize:  2,
+		MaxRetries: 6,
+		OnError:    func(jobs []*airq.Job, err error) { failed += len(jobs) },
+	})
+	created := &airq.Job{Content: "created", Strategy: airq.CreateStrategy}
+	if err := p.Push(&airq.Job{Content: "foo"}, created, &airq.Job{Content: "bar"}); err != nil {
+		t.Error(err)
+	}
+	if created.ID == "" {
+		t.Error("Expected an id to be set on jobs created")
+	}
+	time.Sleep(200 * time.Millisecond)
+	l, err := net.Listen("tcp", connStr)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	go srv.ServeListener(l)
+
+	if err := p.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := p.Push(&airq.Job{Content: "baz"}); !errors.Is(err, client.ErrClosed) {
+		t.Error("Expected ErrClosed, got", err)
+	}
+	if failed != 0 {
+		t.Error("Expected all jobs to be pushed, failed", failed)
+	}
+	if n, _ := q.Pending(); n != 3 {
+		t.Error("Expected 3 jobs pending, got", n)
+	}
+	if j, _ := q.Get(created.ID); j == nil {
+		t.Error("Expected job to be pushed with its id", created.ID)
+	}
+}
+
+func TestServiceTLSAuth(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	ca := newCert(t, nil)
+	serverCert, clientCert := newCert(t, &ca), newCert(t, &ca)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	srv := server.New(q,
+		server.WithTLS(serverCert),
+		server.WithClientCAs(pool),
+		server.WithAuth(server.TokenAuth{
+			"producer": server.Grant{Operations: []string{"Push"}},
+			"admin":    server.Grant{Queues: []string{q.Name}},
+		}),
+	)
+	connStr := serve(t, srv)
+	defer srv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	producer, err := client.Dial(connStr, client.WithTLS(pool), client.WithCertificate(clientCert), client.WithToken("producer"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer producer.Close()
+	if _, err := producer.Push(ctx, &airq.Job{Content: "foo"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := producer.Pending(ctx); status.Code(err) != codes.PermissionDenied {
+		t.Error("Expected permission to be denied, got", err)
+	}
+
+	admin, err := client.Dial(connStr, client.WithTLS(pool), client.WithCertificate(clientCert), client.WithToken("admin"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer admin.Close()
+	if n, err := admin.Pending(ctx); err != nil || n != 1 {
+		t.Error("Expected 1 job pending, got", n, err)
+	}
+
+	unknown, _ := client.Dial(connStr, client.WithTLS(pool), client.WithCertificate(clientCert), client.WithToken("unknown"))
+	defer unknown.Close()
+	if _, err := unknown.Pending(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Error("Expected an unauthenticated error, got", err)
+	}
+
+	anonymous, _ := client.Dial(connStr, client.WithTLS(pool), client.WithToken("admin"))
+	defer anonymous.Close()
+	if _, err := anonymous.Pending(ctx); err == nil {
+		t.Error("Expected an error without client certificate")
+	}
+}
+
+// newCert generates a certificate for 127.0.0.1 signed by parent, a CA
+// certificate if parent is nil.
+func newCert(t *testing.T, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "airq"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	parentCert, parentKey := tmpl, interface{}(key)
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parentCert, parentKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestServiceListeners(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	srv := server.New(q)
+	defer srv.Stop()
+	addr := serve(t, srv)
+	socket := t.TempDir() + "/airq.sock"
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	go srv.ServeListener(l)
+
+	for _, addr := range []string{addr, "unix:" + socket} {
+		cli, err := client.Dial(addr)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, err := cli.Push(context.Background(), &airq.Job{Content: addr}); err != nil {
+			t.Error(addr, err)
+		}
+		cli.Close()
+	}
+	if n, _ := q.Pending(); n != 2 {
+		t.Error("Expected a job pushed through each listener, got", n)
+	}
+}
+
+func TestServiceOptions(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	var serverCalls, clientCalls int
+	socket := "unix:" + t.TempDir() + "/airq.sock"
+	srv := server.New(q,
+		server.WithDrainTimeout(time.Second),
+		server.WithUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			serverCalls++
+			return handler(ctx, req)
+		}),
+	)
+	l, err := net.Listen("unix", strings.TrimPrefix(socket, "unix:"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	served := make(chan error)
+	go func() { served <- srv.ServeListener(l) }()
+
+	cli, err := client.Dial(socket, client.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		clientCalls++
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer cli.Close()
+	if _, err := cli.Push(context.Background(), &airq.Job{Content: "foo"}); err != nil {
+		t.Error(err)
+	}
+	if serverCalls != 1 || clientCalls != 1 {
+		t.Error("Expected interceptors to be called once, got", serverCalls, clientCalls)
+	}
+
+	consumed := make(chan error)
+	go func() {
+		consumed <- cli.Consume(context.Background(), &client.ConsumeOptions{Sleep: 10 * time.Millisecond}, func(*airq.Job) error { return nil })
+	}()
+	time.Sleep(100 * time.Millisecond)
+	srv.GracefulStop()
+	select {
+	case err := <-consumed:
+		if err != nil {
+			t.Error("Expected subscription to end, got", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Expected subscription to end on graceful stop")
+	}
+	if err := <-served; err != nil {
+		t.Error(err)
+	}
 }
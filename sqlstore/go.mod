@@ -0,0 +1,19 @@
+module github.com/jney/airq/sqlstore
+
+go 1.18
+
+require (
+	github.com/jney/airq v0.0.0-20261018223030-1454a989bcd5
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.17
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/gomodule/redigo v1.8.5 // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/hashicorp/go-multierror v1.1.1 // indirect
+	github.com/rs/xid v1.3.0 // indirect
+	github.com/shamaton/msgpack/v2 v2.1.0 // indirect
+	github.com/shamaton/msgpackgen v0.3.0 // indirect
+)
@@ -0,0 +1,326 @@
+// Package sqlstore keeps the jobs of airq queues in a SQL database, Postgres or
+// SQLite, for services without Redis. On Postgres, jobs are popped and reserved
+// with SELECT ... FOR UPDATE SKIP LOCKED so that consumers don't wait for each
+// other, SQLite serializing writes:
+//
+//	store, err := sqlstore.New(db, "emails", sqlstore.WithDialect(sqlstore.SQLite))
+//	if err != nil {
+//		return err
+//	}
+//	if err := store.CreateTable(); err != nil {
+//		return err
+//	}
+//	q := airq.New("emails", airq.WithStore(store))
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jney/airq"
+)
+
+// Dialect is the SQL dialect of the database.
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	SQLite
+)
+
+// DefaultTable is the table of the jobs, shared by queues.
+const DefaultTable = "airq_jobs"
+
+// ErrInvalidTable is returned by New when the table set by WithTable is not an
+// identifier of letters, digits and underscores.
+var ErrInvalidTable = errors.New("invalid table name")
+
+// Store is an airq.Store keeping the jobs of a queue in a table. Pending jobs
+// have a due time, reserved ones a reservation deadline.
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+	queue   string
+	table   string
+}
+
+type Option func(*Store)
+
+// WithDialect sets the dialect of the database, Postgres by default.
+func WithDialect(d Dialect) Option { return func(s *Store) { s.dialect = d } }
+
+// tableName matches the table names accepted by WithTable, interpolated in
+// the queries.
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithTable sets the table of the jobs, DefaultTable by default.
+func WithTable(name string) Option { return func(s *Store) { s.table = name } }
+
+// New returns a store of the jobs of the named queue in db.
+func New(db *sql.DB, queue string, opts ...Option) (*Store, error) {
+	s := &Store{db: db, queue: queue, table: DefaultTable}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !tableName.MatchString(s.table) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTable, s.table)
+	}
+	return s, nil
+}
+
+// CreateTable creates the table of the jobs and its indexes if missing.
+func (s *Store) CreateTable() error {
+	blob := "BYTEA"
+	if s.dialect == SQLite {
+		blob = "BLOB"
+	}
+	for _, query := range []string{
+		`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+			queue TEXT NOT NULL,
+			id TEXT NOT NULL,
+			value ` + blob + ` NOT NULL,
+			due BIGINT,
+			reserved_until BIGINT,
+			PRIMARY KEY (queue, id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + s.table + `_due ON ` + s.table + ` (queue, due, id)`,
+		`CREATE INDEX IF NOT EXISTS ` + s.table + `_reserved ON ` + s.table + ` (queue, reserved_until)`,
+	} {
+		if _, err := s.db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Push(jobs ...airq.StoredJob) (replaced [][]byte, err error) {
+	err = s.tx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(s.rebind(`INSERT INTO ` + s.table + ` (queue, id, value, due) VALUES (?, ?, ?, ?)
+			ON CONFLICT (queue, id) DO UPDATE SET value = excluded.value, due = excluded.due`))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, j := range jobs {
+			var old []byte
+			err := tx.QueryRow(s.rebind(`SELECT value FROM `+s.table+` WHERE queue = ? AND id = ?`), s.queue, j.ID).Scan(&old)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if old != nil {
+				replaced = append(replaced, old)
+			}
+			if _, err := stmt.Exec(s.queue, j.ID, j.Value, j.When); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return replaced, err
+}
+
+func (s *Store) Pop(now int64, limit int) (values [][]byte, err error) {
+	err = s.tx(func(tx *sql.Tx) error {
+		var ids []string
+		if ids, values, err = s.due(tx, now, limit); err != nil || len(ids) == 0 {
+			return err
+		}
+		_, err := tx.Exec(s.rebind(`DELETE FROM `+s.table+` WHERE queue = ? AND id IN (`+in(ids)+`)`), args(s.queue, ids)...)
+		return err
+	})
+	return values, err
+}
+
+func (s *Store) Reserve(now int64, limit int, deadline int64) (values [][]byte, err error) {
+	err = s.tx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(s.rebind(`UPDATE `+s.table+` SET due = ?, reserved_until = NULL
+			WHERE queue = ? AND id IN (SELECT id FROM `+s.table+` WHERE queue = ? AND reserved_until <= ?`+s.skipLocked()+`)`),
+			now, s.queue, s.queue, now); err != nil {
+			return err
+		}
+		var ids []string
+		if ids, values, err = s.due(tx, now, limit); err != nil || len(ids) == 0 {
+			return err
+		}
+		_, err := tx.Exec(s.rebind(`UPDATE `+s.table+` SET due = NULL, reserved_until = ? WHERE queue = ? AND id IN (`+in(ids)+`)`),
+			append([]interface{}{deadline}, args(s.queue, ids)...)...)
+		return err
+	})
+	return values, err
+}
+
+// due returns the ids and values of up to limit jobs due at now, by time,
+// locking them.
+func (s *Store) due(tx *sql.Tx, now int64, limit int) (ids []string, values [][]byte, err error) {
+	rows, err := tx.Query(s.rebind(`SELECT id, value FROM `+s.table+` WHERE queue = ? AND due <= ?
+		ORDER BY due, id LIMIT ?`+s.skipLocked()), s.queue, now, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var value []byte
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, nil, err
+		}
+		ids, values = append(ids, id), append(values, value)
+	}
+	return ids, values, rows.Err()
+}
+
+func (s *Store) Ack(ids ...string) (n int, removed [][]byte, err error) {
+	err = s.tx(func(tx *sql.Tx) error {
+		if n, err = s.exec(tx, `UPDATE `+s.table+` SET reserved_until = NULL
+			WHERE queue = ? AND reserved_until IS NOT NULL AND id IN (`+in(ids)+`)`, args(s.queue, ids)...); err != nil {
+			return err
+		}
+		removed, err = s.values(tx, `DELETE FROM `+s.table+`
+			WHERE queue = ? AND due IS NULL AND reserved_until IS NULL AND id IN (`+in(ids)+`) RETURNING value`, args(s.queue, ids)...)
+		return err
+	})
+	return n, removed, err
+}
+
+func (s *Store) Nack(when int64, ids ...string) (int, error) {
+	return s.exec(s.db, `UPDATE `+s.table+` SET due = ?, reserved_until = NULL
+		WHERE queue = ? AND reserved_until IS NOT NULL AND id IN (`+in(ids)+`)`, append([]interface{}{when}, args(s.queue, ids)...)...)
+}
+
+func (s *Store) List(offset, limit int) ([][]byte, error) {
+	return s.values(s.db, `SELECT value FROM `+s.table+` WHERE queue = ? AND due IS NOT NULL
+		ORDER BY due, id LIMIT ? OFFSET ?`, s.queue, limit, offset)
+}
+
+func (s *Store) Get(id string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRow(s.rebind(`SELECT value FROM `+s.table+` WHERE queue = ? AND id = ?`), s.queue, id).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (s *Store) Reschedule(id string, old, value []byte, when int64) (bool, error) {
+	n, err := s.exec(s.db, `UPDATE `+s.table+` SET value = ?, due = ?
+		WHERE queue = ? AND id = ? AND due IS NOT NULL AND value = ?`, value, when, s.queue, id, old)
+	if err != nil || n == 1 {
+		return n == 1, err
+	}
+	var pending bool
+	err = s.db.QueryRow(s.rebind(`SELECT due IS NOT NULL FROM `+s.table+` WHERE queue = ? AND id = ?`), s.queue, id).Scan(&pending)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !pending) {
+		return false, airq.ErrNotFound
+	}
+	return false, err
+}
+
+func (s *Store) Remove(ids ...string) ([][]byte, error) {
+	return s.values(s.db, `DELETE FROM `+s.table+` WHERE queue = ? AND id IN (`+in(ids)+`) RETURNING value`, args(s.queue, ids)...)
+}
+
+func (s *Store) Count() (n int64, err error) {
+	err = s.db.QueryRow(s.rebind(`SELECT COUNT(due) FROM `+s.table+` WHERE queue = ?`), s.queue).Scan(&n)
+	return n, err
+}
+
+func (s *Store) Stats(now int64) (stats airq.Stats, err error) {
+	err = s.db.QueryRow(s.rebind(`SELECT COUNT(due), COUNT(CASE WHEN due <= ? THEN 1 END), COUNT(reserved_until)
+		FROM `+s.table+` WHERE queue = ?`), now, s.queue).Scan(&stats.Pending, &stats.Due, &stats.Reserved)
+	return stats, err
+}
+
+func (s *Store) Purge() ([][]byte, error) {
+	return s.values(s.db, `DELETE FROM `+s.table+` WHERE queue = ? RETURNING value`, s.queue)
+}
+
+// tx runs f in a transaction, committed if f succeeds.
+func (s *Store) tx(f func(*sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// exec runs query and returns the count of rows affected.
+func (s *Store) exec(db interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+}, query string, args ...interface{}) (int, error) {
+	res, err := db.Exec(s.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// values runs query and returns the values of its rows.
+func (s *Store) values(db interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+}, query string, args ...interface{}) ([][]byte, error) {
+	rows, err := db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values [][]byte
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func (s *Store) skipLocked() string {
+	if s.dialect == Postgres {
+		return " FOR UPDATE SKIP LOCKED"
+	}
+	return ""
+}
+
+// rebind replaces the ? placeholders of query by $1, $2, ... on Postgres.
+func (s *Store) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// in returns the placeholders of ids.
+func in(ids []string) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+}
+
+// args returns queue followed by ids.
+func args(queue string, ids []string) []interface{} {
+	res := make([]interface{}, 0, len(ids)+1)
+	res = append(res, queue)
+	for _, id := range ids {
+		res = append(res, id)
+	}
+	return res
+}
+
+var _ airq.Store = (*Store)(nil)
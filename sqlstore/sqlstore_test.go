@@ -0,0 +1,87 @@
+package sqlstore_test
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/airqtest"
+	"github.com/jney/airq/sqlstore"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func randomName() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+// testStore runs the store suite on a queue of db, and another queue of the
+// same table being left untouched.
+func testStore(t *testing.T, db *sql.DB, opts ...sqlstore.Option) {
+	store, err := sqlstore.New(db, randomName(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+	otherStore, err := sqlstore.New(db, randomName(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := airq.New(randomName(), airq.WithStore(otherStore))
+	if _, err := other.Push(&airq.Job{Content: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Purge()
+
+	airqtest.TestStore(t, airq.New("test", airq.WithStore(store)))
+	if pending, err := other.Pending(); err != nil || pending != 1 {
+		t.Error("Expected the job of the other queue to be kept, got", pending, err)
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "airq.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testStore(t, db, sqlstore.WithDialect(sqlstore.SQLite), sqlstore.WithTable("jobs"))
+}
+
+// TestPostgres runs on the database of AIRQ_POSTGRES_URL, e.g.
+// postgres://postgres@127.0.0.1/airq?sslmode=disable.
+func TestPostgres(t *testing.T) {
+	url := os.Getenv("AIRQ_POSTGRES_URL")
+	if url == "" {
+		t.Skip("AIRQ_POSTGRES_URL not set")
+	}
+	t.Parallel()
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testStore(t, db)
+}
+
+func TestWithTable(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"", "1jobs", "jobs; DROP TABLE users", "jobs-queue", "public.jobs"} {
+		if _, err := sqlstore.New(nil, "q", sqlstore.WithTable(name)); !errors.Is(err, sqlstore.ErrInvalidTable) {
+			t.Errorf("Expected WithTable(%q) to be invalid, got %v", name, err)
+		}
+	}
+	if _, err := sqlstore.New(nil, "q", sqlstore.WithTable("airq_jobs_2")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,173 @@
+package airq
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// Store keeps the encoded jobs of a queue, Redis being the default one (see
+// NewMemoryStore and the sqlstore package for the others). Times are Unix
+// nanoseconds. A job is pending until a time, reserved until a deadline, or
+// both if pushed again while reserved.
+type Store interface {
+	// Push adds pending jobs, replacing those with the same id, and returns
+	// the values of the replaced ones.
+	Push(jobs ...StoredJob) ([][]byte, error)
+	// Pop removes up to limit jobs due at now and returns their values, by time.
+	Pop(now int64, limit int) ([][]byte, error)
+	// Reserve makes the jobs whose reservation expired at now pending at now,
+	// then reserves up to limit jobs due at now until deadline and returns
+	// their values, by time.
+	Reserve(now int64, limit int, deadline int64) ([][]byte, error)
+	// Ack ends the reservation of jobs, removing those not pending, and
+	// returns the count of reserved ones and the values of the removed ones.
+	Ack(ids ...string) (int, [][]byte, error)
+	// Nack makes reserved jobs pending at when and returns their count.
+	Nack(when int64, ids ...string) (int, error)
+	// List returns the values of pending jobs by time, skipping the first
+	// offset ones.
+	List(offset, limit int) ([][]byte, error)
+	// Get returns the value of a pending or reserved job, nil if not found.
+	Get(id string) ([]byte, error)
+	// Reschedule sets the value and time of a pending job if its value is
+	// still old, returning false otherwise and ErrNotFound if it's not pending.
+	Reschedule(id string, old, value []byte, when int64) (bool, error)
+	// Remove removes jobs and returns their values.
+	Remove(ids ...string) ([][]byte, error)
+	// Count returns the count of pending jobs.
+	Count() (int64, error)
+	// Stats returns the job counters at now.
+	Stats(now int64) (Stats, error)
+	// Purge removes all the jobs and returns their values.
+	Purge() ([][]byte, error)
+}
+
+// StoredJob is a job given to a Store.
+type StoredJob struct {
+	ID    string
+	When  int64
+	Value []byte
+}
+
+// WithStore keeps the jobs of the queue in s instead of Redis. Queues are only
+// registered (see ListQueues) in Redis.
+func WithStore(s Store) Option { return func(q *Queue) { q.store = s } }
+
+// redisStore runs the scripts of the queue.
+type redisStore struct{ q *Queue }
+
+func (s redisStore) Push(jobs ...StoredJob) ([][]byte, error) {
+	q := s.q
+	// ids and times are read from the values
+	keysAndArgs := redis.Args{q.key(), q.valuesKey()}
+	for _, j := range jobs {
+		keysAndArgs = keysAndArgs.Add(j.Value)
+	}
+	c, release := q.exec()
+	defer release()
+	return redis.ByteSlices(pushScript.Do(c, keysAndArgs...))
+}
+
+func (s redisStore) Pop(now int64, limit int) ([][]byte, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	return redis.ByteSlices(popJobsScript.Do(c, q.key(), q.valuesKey(), now, limit))
+}
+
+func (s redisStore) Reserve(now int64, limit int, deadline int64) ([][]byte, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	return redis.ByteSlices(reserveScript.Do(c, q.key(), q.valuesKey(), q.reservedKey(), now, limit, deadline))
+}
+
+func (s redisStore) Ack(ids ...string) (int, [][]byte, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	res, err := redis.Values(ackScript.Do(c, redis.Args{q.key(), q.valuesKey(), q.reservedKey()}.AddFlat(ids)...))
+	if err != nil {
+		return 0, nil, err
+	}
+	var n int
+	var removed [][]byte
+	if _, err := redis.Scan(res, &n, &removed); err != nil {
+		return 0, nil, err
+	}
+	return n, removed, nil
+}
+
+func (s redisStore) Nack(when int64, ids ...string) (int, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	return redis.Int(nackScript.Do(c, redis.Args{q.key(), q.reservedKey(), when}.AddFlat(ids)...))
+}
+
+func (s redisStore) List(offset, limit int) (values [][]byte, err error) {
+	q := s.q
+	err = q.idempotent(func(c Executor) (err error) {
+		values, err = redis.ByteSlices(peekScript.Do(c, q.key(), q.valuesKey(), offset, limit))
+		return err
+	})
+	return values, err
+}
+
+func (s redisStore) Get(id string) (b []byte, err error) {
+	q := s.q
+	err = q.idempotent(func(c Executor) (err error) {
+		b, err = redis.Bytes(c.Do("HGET", q.valuesKey(), id))
+		return err
+	})
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (s redisStore) Reschedule(id string, old, value []byte, when int64) (bool, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	ok, err := redis.Int(rescheduleScript.Do(c, q.key(), q.valuesKey(), id, old, value, when))
+	if err == nil && ok == 0 {
+		err = ErrNotFound
+	}
+	return ok == 1, err
+}
+
+func (s redisStore) Remove(ids ...string) ([][]byte, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	return redis.ByteSlices(removeScript.Do(c, redis.Args{q.key(), q.valuesKey(), q.reservedKey()}.AddFlat(ids)...))
+}
+
+func (s redisStore) Count() (n int64, err error) {
+	q := s.q
+	err = q.idempotent(func(c Executor) error {
+		n, err = redis.Int64(c.Do("ZCARD", q.key()))
+		return err
+	})
+	return n, err
+}
+
+func (s redisStore) Stats(now int64) (Stats, error) {
+	q := s.q
+	var counts []int64
+	err := q.idempotent(func(c Executor) (err error) {
+		counts, err = redis.Int64s(statsScript.Do(c, q.key(), q.reservedKey(), now))
+		return err
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	return Stats{Pending: counts[0], Due: counts[1], Reserved: counts[2]}, nil
+}
+
+func (s redisStore) Purge() ([][]byte, error) {
+	q := s.q
+	c, release := q.exec()
+	defer release()
+	return redis.ByteSlices(purgeScript.Do(c, q.key(), q.valuesKey(), q.reservedKey()))
+}
@@ -0,0 +1,18 @@
+package airq_test
+
+import (
+	"testing"
+
+	"github.com/jney/airq"
+	"github.com/jney/airq/airqtest"
+)
+
+func TestRedisStore(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+	airqtest.TestStore(t, q)
+}
+
+func TestMemoryStore(t *testing.T) {
+	airqtest.TestStore(t, airq.New(randomName(), airq.WithStore(airq.NewMemoryStore())))
+}
@@ -0,0 +1,145 @@
+package airq
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Serializer encodes values into job payloads and decodes them back.
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONSerializer is the default Serializer, based on encoding/json.
+type JSONSerializer struct{}
+
+func (JSONSerializer) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (JSONSerializer) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// TypedQueue pushes values of type T into a queue, serialized in the job
+// payload and tagged with a subject.
+type TypedQueue[T any] struct {
+	*Queue
+	Serializer Serializer
+	Subject    string
+}
+
+// NewTypedQueue defines a TypedQueue on top of q. A nil serializer defaults to JSONSerializer.
+func NewTypedQueue[T any](q *Queue, subject string, s Serializer) *TypedQueue[T] {
+	if s == nil {
+		s = JSONSerializer{}
+	}
+	return &TypedQueue[T]{Queue: q, Serializer: s, Subject: subject}
+}
+
+// Encode builds a job holding v. The job can be customized (When, Strategy, ...)
+// before being pushed.
+func (t *TypedQueue[T]) Encode(v T) (*Job, error) {
+	b, err := t.Serializer.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Job{Payload: b, Subject: t.Subject}, nil
+}
+
+// Decode returns the value held by j. Jobs with an empty payload are decoded
+// from their Content, for jobs pushed by hand.
+func (t *TypedQueue[T]) Decode(j *Job) (v T, err error) {
+	data := j.Payload
+	if len(data) == 0 {
+		data = []byte(j.Content)
+	}
+	err = t.Serializer.Unmarshal(data, &v)
+	return v, err
+}
+
+// Push encodes and pushes values to be processed now.
+func (t *TypedQueue[T]) Push(values ...T) ([]string, error) {
+	jobs := make([]*Job, 0, len(values))
+	for _, v := range values {
+		j, err := t.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return t.Queue.Push(jobs...)
+}
+
+// Handler returns a Handler decoding jobs before calling fn.
+func (t *TypedQueue[T]) Handler(fn func(T) error) Handler {
+	return func(j *Job) error {
+		v, err := t.Decode(j)
+		if err != nil {
+			return fmt.Errorf("can't decode job %s: %w", j.ID, err)
+		}
+		return fn(v)
+	}
+}
+
+// Handler processes a single job.
+type Handler func(*Job) error
+
+// Router dispatches jobs to the handler registered for their Subject.
+type Router struct {
+	handlers map[string]Handler
+	// NotFound handles jobs without registered handler, returns an error if nil.
+	NotFound Handler
+	// OnError is called with each error returned by handlers, or by the
+	// queue (with a nil job) when used as a Loop callback.
+	OnError func(*Job, error)
+}
+
+// NewRouter defines an empty Router
+func NewRouter() *Router {
+	return &Router{handlers: make(map[string]Handler)}
+}
+
+// Handle registers the handler for the given subject.
+func (r *Router) Handle(subject string, h Handler) {
+	r.handlers[subject] = h
+}
+
+// Dispatch calls the matching handler for each job.
+func (r *Router) Dispatch(jobs ...*Job) error {
+	var mErr error
+	for _, j := range jobs {
+		if err := r.dispatch(j); err != nil {
+			mErr = multierror.Append(mErr, err)
+			if r.OnError != nil {
+				r.OnError(j, err)
+			}
+		}
+	}
+	return mErr
+}
+
+func (r *Router) dispatch(j *Job) error {
+	h, ok := r.handlers[j.Subject]
+	if !ok {
+		h = r.NotFound
+	}
+	if h == nil {
+		return fmt.Errorf("no handler for subject %q (job %s)", j.Subject, j.ID)
+	}
+	return h(j)
+}
+
+// Process dispatches jobs given to a Loop callback, returning the error of the
+// queue along with those of the handlers.
+func (r *Router) Process(jobs []*Job, err error) error {
+	var mErr error
+	if err != nil {
+		mErr = multierror.Append(mErr, err)
+		if r.OnError != nil {
+			r.OnError(nil, err)
+		}
+	}
+	if err := r.Dispatch(jobs...); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	return mErr
+}
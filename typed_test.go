@@ -0,0 +1,88 @@
+package airq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+type email struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+func TestTypedQueue(t *testing.T) {
+	q, teardown := setup(t)
+	defer teardown()
+
+	tq := NewTypedQueue[email](q, "email", nil)
+	if _, err := tq.Push(email{To: "a@example.com", Body: "hello"}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	var got email
+	r := NewRouter()
+	r.Handle("email", tq.Handler(func(e email) error {
+		got = e
+		return nil
+	}))
+
+	jobs, err := q.PopJobs(1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := r.Dispatch(jobs...); err != nil {
+		t.Error(err)
+	}
+	if got.To != "a@example.com" || got.Body != "hello" {
+		t.Error("Expected email to be decoded, got", got)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	var handled []string
+	r := NewRouter()
+	r.Handle("a", func(j *Job) error {
+		handled = append(handled, j.ID)
+		return nil
+	})
+	r.Handle("b", func(j *Job) error { return errors.New("failed") })
+
+	var failed []string
+	r.OnError = func(j *Job, err error) { failed = append(failed, j.ID) }
+
+	err := r.Dispatch(
+		&Job{ID: "1", Subject: "a"},
+		&Job{ID: "2", Subject: "b"},
+		&Job{ID: "3", Subject: "unknown"},
+	)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if len(handled) != 1 || handled[0] != "1" {
+		t.Error("Expected job 1 to be handled, got", handled)
+	}
+	if len(failed) != 2 {
+		t.Error("Expected jobs 2 and 3 to fail, got", failed)
+	}
+
+	r.NotFound = func(j *Job) error { return nil }
+	if err := r.Dispatch(&Job{ID: "4", Subject: "unknown"}); err != nil {
+		t.Error(err)
+	}
+
+	// without OnError, errors are returned
+	r.OnError = nil
+	err = r.Process([]*Job{{ID: "5", Subject: "b"}}, errors.New("pop failed"))
+	if mErr, ok := err.(*multierror.Error); !ok || len(mErr.Errors) != 2 {
+		t.Error("Expected the queue and handler errors, got", err)
+	}
+	if err := r.Process([]*Job{{ID: "6", Subject: "a"}}, nil); err != nil {
+		t.Error(err)
+	}
+}